@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+)
+
+const adminUsage = "usage: admin grant <email or username>"
+
+// runAdminCommand implements the "admin" subcommand and returns the exit
+// code. grant promotes an existing account to admin, which is how the first
+// admin is created since the admin API needs one already.
+func runAdminCommand(admins usecaseinterfaces.AdminUsecaseInterface, args []string) int {
+	if len(args) != 2 || args[0] != "grant" {
+		fmt.Fprintln(os.Stderr, adminUsage)
+		return 2
+	}
+
+	user, err := admins.PromoteToAdmin(context.Background(), args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to grant admin: %v\n", err)
+		return 1
+	}
+	fmt.Printf("%s (%s) is now an admin\n", user.Username, user.ID)
+	return 0
+}
@@ -2,12 +2,20 @@ package main
 
 import (
 	"auth/internal/delivery/http"
-	"log"
+	"context"
+	"log/slog"
+	nethttp "net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 	// "github.com/joho/godotenv"
 
+	"auth/internal/config"
+	"auth/internal/delivery/http/cookie"
 	handlers "auth/internal/delivery/http/handlers"
+	jobs "auth/internal/jobs"
 	repository "auth/internal/repository"
 	services "auth/internal/services"
 	usecase "auth/internal/usecase"
@@ -21,6 +29,11 @@ import (
 
 	db "auth/internal/infrastructure/db"
 	cache "auth/internal/infrastructure/cache"
+	"auth/internal/infrastructure/health"
+	"auth/internal/infrastructure/logging"
+	"auth/internal/infrastructure/metrics"
+	"auth/internal/infrastructure/tracing"
+	"auth/pkg/revocation"
 
 )
 
@@ -42,44 +55,187 @@ func main() {
 	// if err := godotenv.Load("../.env"); err != nil {
     //     log.Println("No .env file found, relying on system environment variables")
     // }
-	ginMode := os.Getenv("GIN_MODE")
-	if ginMode == "" {
-		ginMode = gin.DebugMode
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
 	}
-	gin.SetMode(ginMode)
+	logger, err := logging.New(os.Stdout, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		slog.Error("Invalid logging configuration", "error", err)
+		os.Exit(1)
+	}
+	// Also routes the standard log package, used by some dependencies
+	slog.SetDefault(logger)
+	slog.Info("Loaded configuration", "config", cfg.String())
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:     cfg.Tracing.Exporter,
+		File:         cfg.Tracing.File,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		slog.Error("Failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+	gin.SetMode(cfg.Server.GinMode)
 
 	// --- 1. Database Connection and Migration ---
-	database := db.NewPostgresDB()
-	db.RunMigrations(database)
+	database := db.NewPostgresDB(cfg.Database.URL)
+	migrator, err := db.NewMigrator(database)
+	if err != nil {
+		slog.Error("Failed to load migrations", "error", err)
+		os.Exit(1)
+	}
+	// "migrate up|down|status" manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrateCommand(migrator, os.Args[2:]))
+	}
+	db.RunMigrations(migrator)
 
-	redis := cache.NewRedisClient()
-	log.Println(redis)
+	redis := cache.NewRedisClient(cfg.Redis.Addr, cfg.Redis.Password)
+	sqlDB, err := database.DB()
+	if err != nil {
+		slog.Error("Failed to access the database pool", "error", err)
+		os.Exit(1)
+	}
+	metrics.RegisterPoolStats(sqlDB, redis)
 
 	// --- 2. Component Initialization (from bottom-up) ---
 	// Repositories
 	userRepo := repository.NewUserRepo(database)
-	sessionRepo := repository.NewSessionRepository(database)
+	auditRepo := repository.NewAuditRepo(database)
+	exportRepo := repository.NewDataExportRepo(database)
+	personalTokenRepo := repository.NewPersonalTokenRepo(database)
+	communityRepo := repository.NewCommunityRepo(database)
+	sessionRepo := repository.NewSessionRepository(database, revocation.NewPublisher(redis))
+	// Cache the session and account lookups made by AuthMiddleware; a zero TTL
+	// disables the cache.
+	if cfg.Sessions.CacheTTL > 0 {
+		userRepo = repository.NewCachedUserRepository(userRepo, redis, cfg.Sessions.CacheTTL)
+		sessionRepo = repository.NewCachedSessionRepository(sessionRepo, redis, cfg.Sessions.CacheTTL)
+	}
 
 	// Services
-	// TODO: Replace these hardcoded secrets with environment variables in a production setup.
-	accessSecret := os.Getenv("ACCESS_SECRET")
-	refreshSecret := os.Getenv("REFRESH_SECRET")
-	tokenService := services.NewTokenService(accessSecret, refreshSecret, 15*time.Minute, 30*24*time.Hour)
+	tokenService := services.NewTokenService(cfg.Tokens.AccessSecret, cfg.Tokens.RefreshSecret, cfg.Tokens.ActionSecret, cfg.Tokens.AccessTTL, cfg.Tokens.RefreshTTL)
 
 	// Use Cases
-	userUsecase := usecase.NewUserUsecase(userRepo, sessionRepo, tokenService)
-	sessionUsecase := usecase.NewSessionUsecase(sessionRepo, tokenService)
+	sessionPolicy := usecase.SessionPolicy{
+		TTL:               cfg.Tokens.RefreshTTL,
+		IdleTimeout:       cfg.Sessions.IdleTimeout,
+		MaxLifetime:       cfg.Sessions.MaxLifetime,
+		TouchInterval:     cfg.Sessions.TouchInterval,
+		MaxSessions:       cfg.Sessions.MaxPerUser,
+		MaxSessionsByRole: cfg.Sessions.MaxByRole,
+		EvictLRU:          cfg.Sessions.LimitPolicy == "evict_lru",
+	}
+	// Counts the background jobs and the notifications requests leave
+	// running, so shutdown waits for both
+	var workers sync.WaitGroup
+	notifier := newNotifier(cfg.Notifications)
+	loginAlerts := usecase.LoginAlerts{
+		Notifier: notifier,
+		Mailer:   newMailer(cfg.Notifications),
+		BaseURL:  cfg.Server.BaseURL,
+		LinkTTL:  cfg.Sessions.LoginAlertLinkTTL,
+		Tasks:    &workers,
+	}
+	userUsecase := usecase.NewUserUsecase(userRepo, sessionRepo, personalTokenRepo, auditRepo, tokenService, sessionPolicy, loginAlerts, cfg.Accounts.DeletionGracePeriod, cfg.Accounts.RegistrationMode)
+	sessionUsecase := usecase.NewSessionUsecase(sessionRepo, userRepo, communityRepo, tokenService, sessionPolicy)
+	adminUsecase := usecase.NewAdminUsecase(userRepo, sessionRepo, personalTokenRepo, auditRepo)
+	// "admin grant <user>" creates the first admin and exits
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		os.Exit(runAdminCommand(adminUsecase, os.Args[2:]))
+	}
+	personalTokenUsecase := usecase.NewPersonalTokenUsecase(personalTokenRepo, userRepo)
+	permissionPolicy := usecase.DefaultPermissionPolicy()
+	authzUsecase := usecase.NewAuthzUsecase(userRepo, communityRepo, permissionPolicy)
+	communityUsecase := usecase.NewCommunityUsecase(communityRepo, repository.NewCommunityInvitationRepo(database), repository.NewCommunityJoinRequestRepo(database), userRepo, permissionPolicy, usecase.CommunityConfig{
+		InvitationTTL: cfg.Communities.InvitationTTL,
+		Notifier:      notifier,
+		Tasks:         &workers,
+	})
+	inviteUsecase := usecase.NewInviteUsecase(repository.NewInviteRepo(database), userRepo, auditRepo, usecase.InviteConfig{
+		MaxActive: cfg.Invites.MaxActive,
+		MaxUses:   cfg.Invites.MaxUses,
+		TTL:       cfg.Invites.TTL,
+	})
+	dataExportUsecase := usecase.NewDataExportUsecase(exportRepo, userRepo, sessionRepo, auditRepo, tokenService, sessionPolicy, usecase.DataExportConfig{
+		Dir:      cfg.Exports.Dir,
+		TTL:      cfg.Exports.TTL,
+		BaseURL:  cfg.Server.BaseURL,
+		Notifier: notifier,
+		Tasks:    &workers,
+	})
 
 	// Handlers
-	userHandler := handlers.NewUserHandler(userUsecase)
-	sessionHandler := handlers.NewSessionHandler(sessionUsecase)
+	cookies := cookie.Config{
+		Enabled:    cfg.Cookies.Enabled,
+		Domain:     cfg.Cookies.Domain,
+		AccessTTL:  cfg.Tokens.AccessTTL,
+		RefreshTTL: cfg.Tokens.RefreshTTL,
+	}
+	userHandler := handlers.NewUserHandler(userUsecase, cookies)
+	sessionHandler := handlers.NewSessionHandler(sessionUsecase, cookies)
+	adminHandler := handlers.NewAdminHandler(adminUsecase, sessionUsecase)
+	dataExportHandler := handlers.NewDataExportHandler(dataExportUsecase)
+	personalTokenHandler := handlers.NewPersonalTokenHandler(personalTokenUsecase)
+	communityHandler := handlers.NewCommunityHandler(communityUsecase)
+	authzHandler := handlers.NewAuthzHandler(authzUsecase)
+	inviteHandler := handlers.NewInviteHandler(inviteUsecase)
+	healthChecker := health.NewChecker(database, redis, migrator, cfg.Server.HealthCheckTimeout)
+	healthHandler := handlers.NewHealthHandler(healthChecker)
+
+	// Background jobs run until a shutdown signal arrives
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	startWorker := func(run func(ctx context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(ctx)
+		}()
+	}
+
+	if interval := cfg.Sessions.JanitorInterval; interval > 0 {
+		janitor := jobs.NewSessionJanitor(sessionRepo, cache.NewLock(redis, "auth:lock:session-janitor", 2*interval), jobs.SessionJanitorConfig{
+			Interval:  interval,
+			Retention: cfg.Sessions.Retention,
+			Mode:      cfg.Sessions.PurgeMode,
+		})
+		startWorker(janitor.Run)
+	}
+
+	if interval := cfg.Accounts.SuspensionExpiryInterval; interval > 0 {
+		startWorker(jobs.NewSuspensionExpiry(userRepo, interval).Run)
+	}
+
+	if interval := cfg.Accounts.DeletionInterval; interval > 0 {
+		startWorker(jobs.NewAccountDeletion(userRepo, dataExportUsecase, interval, cfg.Accounts.DeletionMode).Run)
+	}
+
+	if interval := cfg.Exports.PollInterval; interval > 0 {
+		startWorker(jobs.NewDataExportWorker(dataExportUsecase, interval).Run)
+	}
 
 	// --- 3. Route Configuration ---
 	routerConfig := &http.RouterConfig{
 		UserHandler:    userHandler,
 		SessionHandler: sessionHandler,
+		AdminHandler:   adminHandler,
+		DataExportHandler: dataExportHandler,
+		PersonalTokenHandler: personalTokenHandler,
+		CommunityHandler: communityHandler,
+		AuthzHandler: authzHandler,
+		InviteHandler: inviteHandler,
+		HealthHandler: healthHandler,
 		TokenService:   tokenService,
 		SessionUsecase: sessionUsecase,
+		PersonalTokenUsecase: personalTokenUsecase,
+		AuthzUsecase: authzUsecase,
+		Cookies: cookies,
+		ServiceToken: cfg.Authz.ServiceToken,
+		TrustedProxies: cfg.Server.TrustedProxies,
 	}
 	router := http.SetupRouter(routerConfig)
 
@@ -88,13 +244,99 @@ func main() {
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler, docsUrl))
 
 	// --- 4. Server Startup ---
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	server := &nethttp.Server{
+		Addr:              ":" + cfg.Server.Port,
+		Handler:           router,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
 	}
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Server is starting", "port", cfg.Server.Port)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		slog.Error("Server failed to start", "error", err)
+		os.Exit(1)
+	case <-ctx.Done():
+	}
+	// A second signal kills the process without waiting
+	stop()
+
+	// --- 5. Graceful Shutdown ---
+	// Fail readiness first and keep serving while load balancers notice
+	healthChecker.SetShuttingDown()
+	if cfg.Server.ShutdownDelay > 0 {
+		slog.Info("Shutdown requested, draining traffic", "delay", cfg.Server.ShutdownDelay.String())
+		time.Sleep(cfg.Server.ShutdownDelay)
+	}
+	slog.Info("Shutting down, waiting for requests and workers to finish", "timeout", cfg.Server.ShutdownTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("HTTP server did not drain in time", "error", err)
+	}
+	if !waitGroupWithin(shutdownCtx, &workers) {
+		slog.Error("Background workers did not stop in time")
+	}
+
+	if err := sqlDB.Close(); err != nil {
+		slog.Error("Failed to close the database pool", "error", err)
+	}
+	if err := redis.Close(); err != nil {
+		slog.Error("Failed to close the Redis client", "error", err)
+	}
+	// Flushes the spans of the last requests
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
+	slog.Info("Server stopped")
+}
+
+// waitGroupWithin waits for wg until ctx is done and reports whether wg
+// finished first.
+func waitGroupWithin(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// newNotifier builds the configured notification channels: email when an
+// SMTP host is set and push when a webhook URL is set. Without either,
+// notifications are only logged.
+func newNotifier(cfg config.NotificationsConfig) services.Notifier {
+	var notifiers []services.Notifier
+	if cfg.SMTPHost != "" {
+		notifiers = append(notifiers, newMailer(cfg))
+	}
+	if cfg.PushWebhookURL != "" {
+		notifiers = append(notifiers, services.NewWebhookNotifier(cfg.PushWebhookURL))
+	}
+	if len(notifiers) == 0 {
+		return services.NewLogNotifier()
+	}
+	return services.NewMultiNotifier(notifiers...)
+}
 
-	log.Printf("Server is starting on port %s...", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+// newMailer builds the email channel alone, for messages that must not reach
+// push gateways such as password reset links. Without an SMTP host they are
+// only logged, with the tokens in their links redacted.
+func newMailer(cfg config.NotificationsConfig) services.Notifier {
+	if cfg.SMTPHost == "" {
+		return services.NewLogNotifier()
 	}
+	return services.NewSMTPNotifier(cfg.SMTPHost+":"+cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
 }
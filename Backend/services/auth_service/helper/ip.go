@@ -0,0 +1,23 @@
+package helper
+
+import "net/netip"
+
+// IPRange returns the network an address belongs to for the purpose of
+// recognising returning clients: the /24 for IPv4 and the /48 for IPv6.
+// It returns an empty string when ip cannot be parsed.
+func IPRange(ip string) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ""
+	}
+	addr = addr.Unmap()
+	bits := 48
+	if addr.Is4() {
+		bits = 24
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ""
+	}
+	return prefix.String()
+}
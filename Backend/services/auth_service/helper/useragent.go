@@ -0,0 +1,95 @@
+package helper
+
+import "strings"
+
+// Device types reported by ParseUserAgent.
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+)
+
+// UserAgentInfo is the coarse description of a client derived from its User-Agent header.
+type UserAgentInfo struct {
+	Browser    string
+	OS         string
+	DeviceType string
+}
+
+// ParseUserAgent extracts browser, operating system and device type from a
+// User-Agent header. It only recognises the common families; anything else is
+// reported as "Other".
+func ParseUserAgent(ua string) UserAgentInfo {
+	if strings.TrimSpace(ua) == "" {
+		return UserAgentInfo{Browser: "Unknown", OS: "Unknown", DeviceType: DeviceUnknown}
+	}
+	lower := strings.ToLower(ua)
+
+	return UserAgentInfo{
+		Browser:    parseBrowser(ua),
+		OS:         parseOS(ua),
+		DeviceType: parseDeviceType(ua, lower),
+	}
+}
+
+func parseBrowser(ua string) string {
+	switch {
+	case strings.Contains(ua, "Edg/"), strings.Contains(ua, "EdgA/"), strings.Contains(ua, "EdgiOS/"):
+		return "Edge"
+	case strings.Contains(ua, "OPR/"), strings.Contains(ua, "Opera"):
+		return "Opera"
+	case strings.Contains(ua, "SamsungBrowser/"):
+		return "Samsung Internet"
+	case strings.Contains(ua, "Firefox/"), strings.Contains(ua, "FxiOS/"):
+		return "Firefox"
+	case strings.Contains(ua, "Chrome/"), strings.Contains(ua, "CriOS/"):
+		return "Chrome"
+	case strings.Contains(ua, "Safari/") && strings.Contains(ua, "Version/"):
+		return "Safari"
+	case strings.Contains(ua, "MSIE "), strings.Contains(ua, "Trident/"):
+		return "Internet Explorer"
+	case strings.HasPrefix(ua, "okhttp/"):
+		return "OkHttp"
+	case strings.HasPrefix(ua, "Dart/"):
+		return "Dart"
+	case strings.HasPrefix(ua, "curl/"):
+		return "curl"
+	}
+	return "Other"
+}
+
+func parseOS(ua string) string {
+	switch {
+	case strings.Contains(ua, "Windows"):
+		return "Windows"
+	case strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPad"), strings.Contains(ua, "iPod"):
+		return "iOS"
+	case strings.Contains(ua, "Android"):
+		return "Android"
+	case strings.Contains(ua, "CrOS"):
+		return "ChromeOS"
+	case strings.Contains(ua, "Mac OS X"), strings.Contains(ua, "Macintosh"):
+		return "macOS"
+	case strings.Contains(ua, "Linux"):
+		return "Linux"
+	}
+	return "Other"
+}
+
+func parseDeviceType(ua, lower string) string {
+	switch {
+	case strings.Contains(lower, "bot"), strings.Contains(lower, "crawler"), strings.Contains(lower, "spider"):
+		return DeviceBot
+	case strings.Contains(ua, "iPad"), strings.Contains(ua, "Tablet"),
+		strings.Contains(ua, "Android") && !strings.Contains(ua, "Mobile"):
+		return DeviceTablet
+	case strings.Contains(ua, "Mobi"), strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPod"):
+		return DeviceMobile
+	case strings.Contains(ua, "Windows"), strings.Contains(ua, "Macintosh"),
+		strings.Contains(ua, "X11"), strings.Contains(ua, "CrOS"):
+		return DeviceDesktop
+	}
+	return DeviceUnknown
+}
@@ -0,0 +1,218 @@
+// Package config loads the service settings from an optional YAML file and
+// the environment, applies defaults and validates them at startup.
+//
+// Every setting can be given in the file named by CONFIG_FILE, under the
+// yaml keys below, and overridden by the environment variable named in its
+// env tag. Empty environment variables are treated as unset.
+package config
+
+import (
+	"time"
+)
+
+type Config struct {
+	Server        ServerConfig        `yaml:"server"`
+	Log           LogConfig           `yaml:"log"`
+	Tracing       TracingConfig       `yaml:"tracing"`
+	Database      DatabaseConfig      `yaml:"database"`
+	Redis         RedisConfig         `yaml:"redis"`
+	Tokens        TokenConfig         `yaml:"tokens"`
+	Sessions      SessionConfig       `yaml:"sessions"`
+	Cookies       CookieConfig        `yaml:"cookies"`
+	Accounts      AccountConfig       `yaml:"accounts"`
+	Invites       InviteConfig        `yaml:"invites"`
+	Communities   CommunityConfig     `yaml:"communities"`
+	Exports       ExportConfig        `yaml:"exports"`
+	Authz         AuthzConfig         `yaml:"authz"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+}
+
+type ServerConfig struct {
+	Port    string `yaml:"port" env:"PORT"`
+	GinMode string `yaml:"gin_mode" env:"GIN_MODE"`
+	// BaseURL is the public URL of this service, used in emailed links.
+	BaseURL string `yaml:"base_url" env:"APP_BASE_URL"`
+	// TrustedProxies lists the proxy IPs/CIDRs whose forwarding headers are
+	// used to resolve the client IP.
+	TrustedProxies []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	// WriteTimeout bounds a whole response, including export downloads.
+	WriteTimeout   time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout    time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	MaxHeaderBytes int           `yaml:"max_header_bytes" env:"HTTP_MAX_HEADER_BYTES"`
+	// ShutdownDelay is how long readiness fails before the server stops
+	// accepting connections, so load balancers can route traffic away.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" env:"SHUTDOWN_DELAY"`
+	// ShutdownTimeout is how long in-flight requests and background workers
+	// then get to finish.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// HealthCheckTimeout bounds each dependency check made by /readyz.
+	HealthCheckTimeout time.Duration `yaml:"health_check_timeout" env:"HEALTH_CHECK_TIMEOUT"`
+}
+
+type LogConfig struct {
+	// Level is one of debug, info, warn or error.
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+	// Format is json, or text for reading logs in a terminal.
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+type TracingConfig struct {
+	// Exporter is none, stdout, file or otlp.
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+	// File receives the spans, as JSON, with the file exporter.
+	File string `yaml:"file" env:"TRACING_FILE"`
+	// OTLPEndpoint is the OTLP/HTTP collector URL used by the otlp exporter.
+	OTLPEndpoint string `yaml:"otlp_endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	// SampleRatio is the fraction of new traces recorded. Requests carrying a
+	// traceparent header follow the caller's sampling decision.
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+type DatabaseConfig struct {
+	URL string `yaml:"url" env:"DATABASE_URL" secret:"dsn"`
+}
+
+type RedisConfig struct {
+	Addr     string `yaml:"addr" env:"REDIS_URL"`
+	Password string `yaml:"password" env:"REDIS_PASSWORD" secret:"true"`
+}
+
+// TokenConfig is the single source of token and session lifetimes: the
+// refresh TTL is also the lifetime of a new session and of the auth cookies.
+type TokenConfig struct {
+	AccessSecret  string        `yaml:"access_secret" env:"ACCESS_SECRET" secret:"true"`
+	RefreshSecret string        `yaml:"refresh_secret" env:"REFRESH_SECRET" secret:"true"`
+	AccessTTL     time.Duration `yaml:"access_ttl" env:"ACCESS_TOKEN_TTL"`
+	RefreshTTL    time.Duration `yaml:"refresh_ttl" env:"REFRESH_TOKEN_TTL"`
+	// ActionSecret signs the links sent by email, such as "this wasn't me"
+	// and password reset links.
+	ActionSecret string `yaml:"action_secret" env:"ACTION_SECRET" secret:"true"`
+}
+
+type SessionConfig struct {
+	// CacheTTL is how long session and account status lookups are cached in
+	// Redis; zero disables the cache. It also bounds how long a revoked
+	// session, or a suspended account, can still pass AuthMiddleware when the
+	// change could not be written to Redis.
+	CacheTTL      time.Duration `yaml:"cache_ttl" env:"SESSION_CACHE_TTL"`
+	IdleTimeout   time.Duration `yaml:"idle_timeout" env:"SESSION_IDLE_TIMEOUT"`
+	MaxLifetime   time.Duration `yaml:"max_lifetime" env:"SESSION_MAX_LIFETIME"`
+	TouchInterval time.Duration `yaml:"touch_interval" env:"SESSION_TOUCH_INTERVAL"`
+	MaxPerUser    int           `yaml:"max_per_user" env:"MAX_SESSIONS_PER_USER"`
+	// MaxByRole is written as "admin=10,moderator=5" in the environment.
+	MaxByRole   map[string]int `yaml:"max_by_role" env:"MAX_SESSIONS_BY_ROLE"`
+	LimitPolicy string         `yaml:"limit_policy" env:"SESSION_LIMIT_POLICY"`
+	// JanitorInterval is how often old sessions are purged; zero disables it.
+	JanitorInterval time.Duration `yaml:"janitor_interval" env:"SESSION_JANITOR_INTERVAL"`
+	PurgeMode       string        `yaml:"purge_mode" env:"SESSION_PURGE_MODE"`
+	Retention       time.Duration `yaml:"retention" env:"SESSION_RETENTION"`
+	// LoginAlertLinkTTL is how long the "this wasn't me" link stays valid.
+	LoginAlertLinkTTL time.Duration `yaml:"login_alert_link_ttl" env:"LOGIN_ALERT_LINK_TTL"`
+}
+
+type CookieConfig struct {
+	Enabled bool   `yaml:"enabled" env:"AUTH_COOKIE_MODE"`
+	Domain  string `yaml:"domain" env:"COOKIE_DOMAIN"`
+}
+
+type AccountConfig struct {
+	RegistrationMode         string        `yaml:"registration_mode" env:"REGISTRATION_MODE"`
+	DeletionGracePeriod      time.Duration `yaml:"deletion_grace_period" env:"ACCOUNT_DELETION_GRACE_PERIOD"`
+	DeletionInterval         time.Duration `yaml:"deletion_interval" env:"ACCOUNT_DELETION_INTERVAL"`
+	DeletionMode             string        `yaml:"deletion_mode" env:"ACCOUNT_DELETION_MODE"`
+	SuspensionExpiryInterval time.Duration `yaml:"suspension_expiry_interval" env:"SUSPENSION_EXPIRY_INTERVAL"`
+}
+
+type InviteConfig struct {
+	MaxActive int           `yaml:"max_active" env:"INVITE_MAX_ACTIVE"`
+	MaxUses   int           `yaml:"max_uses" env:"INVITE_MAX_USES"`
+	TTL       time.Duration `yaml:"ttl" env:"INVITE_TTL"`
+}
+
+type CommunityConfig struct {
+	InvitationTTL time.Duration `yaml:"invitation_ttl" env:"COMMUNITY_INVITATION_TTL"`
+}
+
+type ExportConfig struct {
+	Dir          string        `yaml:"dir" env:"EXPORT_DIR"`
+	TTL          time.Duration `yaml:"ttl" env:"EXPORT_TTL"`
+	PollInterval time.Duration `yaml:"poll_interval" env:"EXPORT_POLL_INTERVAL"`
+}
+
+type AuthzConfig struct {
+	// ServiceToken authenticates other services calling the authz API.
+	ServiceToken string `yaml:"service_token" env:"AUTHZ_SERVICE_TOKEN" secret:"true"`
+}
+
+// NotificationsConfig enables email when SMTPHost is set and push when
+// PushWebhookURL is set. Without either, notifications are only logged.
+type NotificationsConfig struct {
+	SMTPHost       string `yaml:"smtp_host" env:"SMTP_HOST"`
+	SMTPPort       string `yaml:"smtp_port" env:"SMTP_PORT"`
+	SMTPUsername   string `yaml:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword   string `yaml:"smtp_password" env:"SMTP_PASSWORD" secret:"true"`
+	SMTPFrom       string `yaml:"smtp_from" env:"SMTP_FROM"`
+	PushWebhookURL string `yaml:"push_webhook_url" env:"PUSH_WEBHOOK_URL" secret:"true"`
+}
+
+// Default returns the settings used for anything not configured.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:    "8080",
+			GinMode: "debug",
+			BaseURL: "http://localhost:8080",
+
+			ReadTimeout:        15 * time.Second,
+			ReadHeaderTimeout:  5 * time.Second,
+			WriteTimeout:       60 * time.Second,
+			IdleTimeout:        2 * time.Minute,
+			MaxHeaderBytes:     1 << 20,
+			ShutdownDelay:      5 * time.Second,
+			ShutdownTimeout:    30 * time.Second,
+			HealthCheckTimeout: 2 * time.Second,
+		},
+		Log: LogConfig{Level: "info", Format: "json"},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			File:        "traces.json",
+			SampleRatio: 1,
+		},
+		Redis: RedisConfig{Addr: "localhost:6379"},
+		Tokens: TokenConfig{
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: 30 * 24 * time.Hour,
+		},
+		Sessions: SessionConfig{
+			CacheTTL:          2 * time.Minute,
+			TouchInterval:     time.Minute,
+			LimitPolicy:       "reject",
+			JanitorInterval:   time.Hour,
+			PurgeMode:         "delete",
+			Retention:         30 * 24 * time.Hour,
+			LoginAlertLinkTTL: 72 * time.Hour,
+		},
+		Accounts: AccountConfig{
+			RegistrationMode:         "open",
+			DeletionGracePeriod:      14 * 24 * time.Hour,
+			DeletionInterval:         time.Hour,
+			DeletionMode:             "delete",
+			SuspensionExpiryInterval: time.Minute,
+		},
+		Invites: InviteConfig{
+			MaxActive: 5,
+			MaxUses:   5,
+			TTL:       7 * 24 * time.Hour,
+		},
+		Communities: CommunityConfig{InvitationTTL: 7 * 24 * time.Hour},
+		Exports: ExportConfig{
+			Dir:          "exports",
+			TTL:          48 * time.Hour,
+			PollInterval: 10 * time.Second,
+		},
+		Notifications: NotificationsConfig{SMTPPort: "587"},
+	}
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[REDACTED]"
+
+// dsnPassword matches the password of a key=value Postgres DSN.
+var dsnPassword = regexp.MustCompile(`(password=)\S+`)
+
+// String renders the configuration as YAML with secrets redacted, so it can be
+// logged at startup.
+func (c *Config) String() string {
+	copied := *c
+	redact(reflect.ValueOf(&copied).Elem())
+	out, err := yaml.Marshal(&copied)
+	if err != nil {
+		return "<unprintable configuration: " + err.Error() + ">"
+	}
+	return string(out)
+}
+
+// redact blanks the fields of v tagged secret. Fields tagged secret:"dsn"
+// keep everything but the password, which helps when debugging connections.
+func redact(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			redact(field)
+			continue
+		}
+		if field.Kind() != reflect.String || field.String() == "" {
+			continue
+		}
+		switch v.Type().Field(i).Tag.Get("secret") {
+		case "true":
+			field.SetString(redacted)
+		case "dsn":
+			field.SetString(redactDSN(field.String()))
+		}
+	}
+}
+
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+		return u.Redacted()
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}"+redacted)
+}
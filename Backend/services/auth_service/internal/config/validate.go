@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// minSecretLength is the shortest signing secret accepted, in bytes.
+const minSecretLength = 32
+
+// maxSessionCacheTTL caps SESSION_CACHE_TTL, and with it how long a revoked
+// session can be served from a stale cache entry.
+const maxSessionCacheTTL = 10 * time.Minute
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	oneOf := func(key, value string, allowed ...string) {
+		check(slices.Contains(allowed, value), "%s must be one of %q, got %q", key, allowed, value)
+	}
+	between := func(key string, value, min, max time.Duration) {
+		check(value >= min && value <= max, "%s must be between %s and %s, got %s", key, min, max, value)
+	}
+
+	errs = append(errs, checkNonNegative(reflect.ValueOf(*c))...)
+
+	port, err := strconv.Atoi(c.Server.Port)
+	check(err == nil && port > 0 && port <= 65535, "PORT must be a TCP port number, got %q", c.Server.Port)
+	oneOf("GIN_MODE", c.Server.GinMode, "debug", "release", "test")
+	baseURL, err := url.Parse(c.Server.BaseURL)
+	check(err == nil && (baseURL.Scheme == "http" || baseURL.Scheme == "https") && baseURL.Host != "",
+		"APP_BASE_URL must be an absolute http(s) URL, got %q", c.Server.BaseURL)
+
+	check(c.Server.ReadHeaderTimeout > 0, "HTTP_READ_HEADER_TIMEOUT must be positive")
+	check(c.Server.MaxHeaderBytes >= 4<<10, "HTTP_MAX_HEADER_BYTES must be at least 4096")
+	check(c.Server.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT must be positive")
+	check(c.Server.HealthCheckTimeout > 0, "HEALTH_CHECK_TIMEOUT must be positive")
+
+	oneOf("LOG_LEVEL", c.Log.Level, "debug", "info", "warn", "error")
+	oneOf("LOG_FORMAT", c.Log.Format, "json", "text")
+	oneOf("TRACING_EXPORTER", c.Tracing.Exporter, "none", "stdout", "file", "otlp")
+	check(c.Tracing.SampleRatio <= 1, "TRACING_SAMPLE_RATIO must be between 0 and 1")
+	check(c.Tracing.Exporter != "file" || c.Tracing.File != "", "TRACING_FILE is required with the file exporter")
+
+	check(c.Database.URL != "", "DATABASE_URL is required")
+	check(c.Redis.Addr != "", "REDIS_URL is required")
+
+	check(len(c.Tokens.AccessSecret) >= minSecretLength, "ACCESS_SECRET must be at least %d bytes", minSecretLength)
+	check(len(c.Tokens.RefreshSecret) >= minSecretLength, "REFRESH_SECRET must be at least %d bytes", minSecretLength)
+	check(len(c.Tokens.ActionSecret) >= minSecretLength, "ACTION_SECRET must be at least %d bytes", minSecretLength)
+	check(c.Tokens.AccessSecret != c.Tokens.RefreshSecret, "ACCESS_SECRET and REFRESH_SECRET must differ")
+	check(c.Tokens.ActionSecret != c.Tokens.AccessSecret && c.Tokens.ActionSecret != c.Tokens.RefreshSecret,
+		"ACTION_SECRET must differ from ACCESS_SECRET and REFRESH_SECRET")
+	between("ACCESS_TOKEN_TTL", c.Tokens.AccessTTL, time.Minute, 24*time.Hour)
+	between("REFRESH_TOKEN_TTL", c.Tokens.RefreshTTL, time.Hour, 365*24*time.Hour)
+	check(c.Tokens.RefreshTTL > c.Tokens.AccessTTL, "REFRESH_TOKEN_TTL must be longer than ACCESS_TOKEN_TTL")
+
+	check(c.Sessions.CacheTTL <= maxSessionCacheTTL, "SESSION_CACHE_TTL must be at most %s", maxSessionCacheTTL)
+	oneOf("SESSION_LIMIT_POLICY", c.Sessions.LimitPolicy, "reject", "evict_lru")
+	oneOf("SESSION_PURGE_MODE", c.Sessions.PurgeMode, "delete", "archive")
+	check(c.Sessions.MaxLifetime == 0 || c.Sessions.MaxLifetime >= c.Tokens.AccessTTL,
+		"SESSION_MAX_LIFETIME must be zero or at least ACCESS_TOKEN_TTL")
+	// LastUsedAt is written at most once per touch interval, so a shorter idle
+	// timeout could expire a session that is in use
+	check(c.Sessions.IdleTimeout == 0 || c.Sessions.IdleTimeout > c.Sessions.TouchInterval,
+		"SESSION_IDLE_TIMEOUT must be zero or longer than SESSION_TOUCH_INTERVAL")
+	check(c.Sessions.LoginAlertLinkTTL > 0, "LOGIN_ALERT_LINK_TTL must be positive")
+
+	oneOf("REGISTRATION_MODE", c.Accounts.RegistrationMode, "open", "invite_only", "closed")
+	oneOf("ACCOUNT_DELETION_MODE", c.Accounts.DeletionMode, "delete", "anonymise")
+
+	check(c.Invites.MaxUses > 0, "INVITE_MAX_USES must be positive")
+	check(c.Invites.TTL > 0, "INVITE_TTL must be positive")
+	check(c.Communities.InvitationTTL > 0, "COMMUNITY_INVITATION_TTL must be positive")
+	check(c.Exports.Dir != "", "EXPORT_DIR is required")
+	check(c.Exports.TTL > 0, "EXPORT_TTL must be positive")
+
+	check(c.Notifications.SMTPHost == "" || c.Notifications.SMTPFrom != "", "SMTP_FROM is required when SMTP_HOST is set")
+
+	return errors.Join(errs...)
+}
+
+// checkNonNegative reports every negative number or duration in v.
+func checkNonNegative(v reflect.Value) []error {
+	var errs []error
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		key := v.Type().Field(i).Tag.Get("env")
+		switch value := field.Interface().(type) {
+		case time.Duration:
+			if value < 0 {
+				errs = append(errs, fmt.Errorf("%s must not be negative", key))
+			}
+		case int:
+			if value < 0 {
+				errs = append(errs, fmt.Errorf("%s must not be negative", key))
+			}
+		case float64:
+			if value < 0 {
+				errs = append(errs, fmt.Errorf("%s must not be negative", key))
+			}
+		case map[string]int:
+			for role, limit := range value {
+				if limit < 0 {
+					errs = append(errs, fmt.Errorf("%s must not be negative for %s", key, role))
+				}
+			}
+		default:
+			if field.Kind() == reflect.Struct {
+				errs = append(errs, checkNonNegative(field)...)
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,103 @@
+// Package cookie delivers tokens to browser clients as cookies instead of in
+// JSON bodies, and holds the names shared by the handlers and middleware.
+package cookie
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// AccessTokenName is the HttpOnly cookie carrying the access token.
+	AccessTokenName = "access_token"
+	// RefreshTokenName is the HttpOnly cookie carrying the refresh token. It is
+	// only sent to the refresh endpoint.
+	RefreshTokenName = "refresh_token"
+	// CSRFTokenName is the JavaScript-readable cookie holding the CSRF token
+	// that must be echoed in CSRFHeader on state-changing requests.
+	CSRFTokenName = "csrf_token"
+	CSRFHeader    = "X-CSRF-Token"
+
+	accessTokenPath  = "/api/v1"
+	refreshTokenPath = "/api/v1/auth/refresh"
+)
+
+// Config controls cookie-based token delivery.
+type Config struct {
+	// Enabled allows clients to ask for cookie delivery at login.
+	Enabled bool
+	// Domain scopes the cookies; empty means the host that set them.
+	Domain     string
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+// SetTokens writes the access token cookie and, when refreshToken is not
+// empty, the refresh token cookie. It makes sure a CSRF cookie exists and
+// returns its value.
+func (c Config) SetTokens(ctx *gin.Context, accessToken, refreshToken string) (string, error) {
+	csrfToken, err := ctx.Cookie(CSRFTokenName)
+	if err != nil || csrfToken == "" {
+		if csrfToken, err = newCSRFToken(); err != nil {
+			return "", err
+		}
+	}
+	c.setTokens(ctx, accessToken, refreshToken, csrfToken)
+	return csrfToken, nil
+}
+
+// StartSession writes the token cookies of a new login with a new CSRF token,
+// so a CSRF token planted or read before the login cannot be reused, and
+// returns it.
+func (c Config) StartSession(ctx *gin.Context, accessToken, refreshToken string) (string, error) {
+	csrfToken, err := newCSRFToken()
+	if err != nil {
+		return "", err
+	}
+	c.setTokens(ctx, accessToken, refreshToken, csrfToken)
+	return csrfToken, nil
+}
+
+func (c Config) setTokens(ctx *gin.Context, accessToken, refreshToken, csrfToken string) {
+	c.set(ctx, AccessTokenName, accessToken, accessTokenPath, c.AccessTTL, true)
+	if refreshToken != "" {
+		c.set(ctx, RefreshTokenName, refreshToken, refreshTokenPath, c.RefreshTTL, true)
+	}
+	c.set(ctx, CSRFTokenName, csrfToken, "/", c.RefreshTTL, false)
+}
+
+// Clear removes every token cookie.
+func (c Config) Clear(ctx *gin.Context) {
+	c.set(ctx, AccessTokenName, "", accessTokenPath, -1, true)
+	c.set(ctx, RefreshTokenName, "", refreshTokenPath, -1, true)
+	c.set(ctx, CSRFTokenName, "", "/", -1, false)
+}
+
+func (c Config) set(ctx *gin.Context, name, value, path string, ttl time.Duration, httpOnly bool) {
+	maxAge := int(ttl.Seconds())
+	if ttl < 0 {
+		maxAge = -1
+	}
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   c.Domain,
+		MaxAge:   maxAge,
+		Secure:   true,
+		HttpOnly: httpOnly,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+func newCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdminActor identifies the administrator performing an action, for the audit trail.
+type AdminActor struct {
+	UserID uuid.UUID
+	IP     string
+}
+
+// AdminUserQuery holds the query parameters of GET /admin/users.
+type AdminUserQuery struct {
+	Query    string `form:"q"`
+	Role     string `form:"role" binding:"omitempty,oneof=user moderator admin"`
+	Status   string `form:"status"`
+	Verified *bool  `form:"verified"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// AdminUserDTO is the administrator's view of an account.
+type AdminUserDTO struct {
+	ID                    uuid.UUID `json:"id"`
+	FullName              string    `json:"full_name"`
+	Email                 string    `json:"email"`
+	Username              string    `json:"username"`
+	PhoneNumber           string    `json:"phone_number"`
+	Country               string    `json:"country"`
+	Role                  string    `json:"role"`
+	Status                string     `json:"status"`
+	StatusReason          string     `json:"status_reason,omitempty"`
+	StatusChangedBy       *uuid.UUID `json:"status_changed_by,omitempty"`
+	StatusChangedAt       *time.Time `json:"status_changed_at,omitempty"`
+	StatusExpiresAt       *time.Time `json:"status_expires_at,omitempty"`
+	IsVerified            bool      `json:"is_verified"`
+	PasswordResetRequired bool      `json:"password_reset_required"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+type AdminUserListResponse struct {
+	Users    []*AdminUserDTO `json:"users"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+	Total    int64           `json:"total"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+	// Until ends the suspension automatically; omit for an indefinite one.
+	Until *time.Time `json:"until"`
+}
+
+type ChangeStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active suspended banned deactivated"`
+	Reason string `json:"reason" binding:"max=500"`
+	// Until ends a suspension automatically; not allowed for other statuses.
+	Until *time.Time `json:"until"`
+}
+
+type ChangeRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user moderator admin"`
+}
+
+// AuditLogQuery holds the query parameters of GET /admin/audit-logs.
+type AuditLogQuery struct {
+	ActorID  string `form:"actor_id" binding:"omitempty,uuid"`
+	TargetID string `form:"target_id" binding:"omitempty,uuid"`
+	Action   string `form:"action"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type AuditLogDTO struct {
+	ID           uuid.UUID         `json:"id"`
+	ActorID      uuid.UUID         `json:"actor_id"`
+	Action       string            `json:"action"`
+	TargetUserID *uuid.UUID        `json:"target_user_id,omitempty"`
+	Details      map[string]string `json:"details,omitempty"`
+	IP           string            `json:"ip,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+type AuditLogListResponse struct {
+	Entries  []*AuditLogDTO `json:"entries"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+	Total    int64          `json:"total"`
+}
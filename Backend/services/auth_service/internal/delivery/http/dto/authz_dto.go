@@ -0,0 +1,33 @@
+package dto
+
+import "github.com/google/uuid"
+
+// AuthzCheckRequest asks whether subject may perform action on resource.
+// Leaving out the resource checks platform-wide permissions only.
+type AuthzCheckRequest struct {
+	Subject  AuthzSubject   `json:"subject" binding:"required"`
+	Action   string         `json:"action" binding:"required"`
+	Resource *AuthzResource `json:"resource"`
+}
+
+type AuthzSubject struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+}
+
+type AuthzResource struct {
+	Type string    `json:"type" binding:"required,oneof=community"`
+	ID   uuid.UUID `json:"id" binding:"required"`
+}
+
+type AuthzCheckResponse struct {
+	Allowed bool `json:"allowed"`
+	// Reason says which role granted the permission, or why it was denied.
+	Reason string `json:"reason"`
+}
+
+// AuthzPrincipal is a subject described by the claims of its access token,
+// as put in the request context by AuthMiddleware.
+type AuthzPrincipal struct {
+	UserID uuid.UUID
+	Roles  []string
+}
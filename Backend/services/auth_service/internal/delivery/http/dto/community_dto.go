@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateCommunityRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	// Slug is the community's unique handle: lowercase letters, digits and
+	// single hyphens.
+	Slug        string `json:"slug" binding:"required,min=3,max=50"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+type CommunityDTO struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// MyCommunityDTO is a community the user belongs to, with their role there.
+type MyCommunityDTO struct {
+	CommunityDTO
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// CommunityMemberQuery holds the query parameters of GET /communities/{id}/members.
+type CommunityMemberQuery struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type CommunityMemberDTO struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	FullName string    `json:"full_name"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type CommunityMemberListResponse struct {
+	Members  []*CommunityMemberDTO `json:"members"`
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"page_size"`
+	Total    int64                 `json:"total"`
+}
+
+type ChangeMemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner moderator member"`
+}
+
+type CreateInvitationRequest struct {
+	// Identification is the invitee's email or username.
+	Identification string `json:"identification" binding:"required"`
+	Role           string `json:"role" binding:"omitempty,oneof=member moderator"`
+}
+
+type InvitationDTO struct {
+	ID          uuid.UUID     `json:"id"`
+	Community   *CommunityDTO `json:"community,omitempty"`
+	CommunityID uuid.UUID     `json:"community_id"`
+	InviteeID   uuid.UUID     `json:"invitee_id"`
+	Invitee     string        `json:"invitee,omitempty"`
+	InvitedBy   uuid.UUID     `json:"invited_by"`
+	Role        string        `json:"role"`
+	Status      string        `json:"status"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+type CreateJoinRequestRequest struct {
+	Message string `json:"message" binding:"max=500"`
+}
+
+type JoinRequestDTO struct {
+	ID          uuid.UUID  `json:"id"`
+	CommunityID uuid.UUID  `json:"community_id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Username    string     `json:"username,omitempty"`
+	Message     string     `json:"message,omitempty"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+}
+
+// SwitchCommunityRequest selects the community a session acts in. A null
+// community_id switches back to no community.
+type SwitchCommunityRequest struct {
+	CommunityID *uuid.UUID `json:"community_id"`
+}
+
+type SwitchCommunityResponse struct {
+	AccessToken string `json:"access_token,omitempty"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataExportDTO reports the state of a personal data export.
+type DataExportDTO struct {
+	ID          uuid.UUID  `json:"id"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	// DownloadURL is set once the archive is ready.
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// PersonalDataArchive is the JSON document handed out by a data export.
+type PersonalDataArchive struct {
+	GeneratedAt      time.Time               `json:"generated_at"`
+	Profile          ExportedProfile         `json:"profile"`
+	Consents         []ExportedConsent       `json:"consents"`
+	Sessions         []SessionHistoryItemDTO `json:"sessions"`
+	ArchivedSessions []SessionHistoryItemDTO `json:"archived_sessions"`
+	SecurityEvents   []ExportedSecurityEvent `json:"security_events"`
+}
+
+type ExportedProfile struct {
+	ID              uuid.UUID  `json:"id"`
+	FullName        string     `json:"full_name"`
+	Email           string     `json:"email"`
+	Username        string     `json:"username"`
+	PhoneNumber     string     `json:"phone_number"`
+	Country         string     `json:"country"`
+	Role            string     `json:"role"`
+	Status          string     `json:"status"`
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+	StatusExpiresAt *time.Time `json:"status_expires_at,omitempty"`
+	IsVerified      bool       `json:"is_verified"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type ExportedConsent struct {
+	Name     string `json:"name"`
+	Accepted bool   `json:"accepted"`
+}
+
+// ExportedSecurityEvent is an audit log entry about the user. IP addresses
+// are only included for actions the user performed themselves.
+type ExportedSecurityEvent struct {
+	Action      string            `json:"action"`
+	PerformedBy string            `json:"performed_by"`
+	Details     map[string]string `json:"details,omitempty"`
+	IP          string            `json:"ip,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+type LivenessResponse struct {
+	Status string `json:"status"`
+}
+
+// DependencyStatus only tells whether a dependency is reachable; the
+// reason it is not is logged, as the probe is public.
+type DependencyStatus struct {
+	Status string `json:"status"`
+}
+
+type ReadinessResponse struct {
+	Status string `json:"status"`
+	// MigrationVersion is the latest migration applied to the database and
+	// LatestMigration the latest one this binary knows.
+	MigrationVersion int64                       `json:"migration_version"`
+	LatestMigration  int64                       `json:"latest_migration"`
+	Checks           map[string]DependencyStatus `json:"checks"`
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invite code statuses.
+const (
+	InviteStatusActive    = "active"
+	InviteStatusExpired   = "expired"
+	InviteStatusExhausted = "exhausted"
+	InviteStatusRevoked   = "revoked"
+)
+
+type CreateInviteRequest struct {
+	// MaxUses defaults to 1. Only administrators may use 0, for unlimited.
+	MaxUses *int `json:"max_uses" binding:"omitempty,min=0"`
+	// ExpiresAt defaults to the configured invite lifetime.
+	ExpiresAt *time.Time `json:"expires_at"`
+	Note      string     `json:"note" binding:"max=200"`
+}
+
+// InviteQuery holds the query parameters of the invite listings.
+type InviteQuery struct {
+	CreatedBy string `form:"created_by" binding:"omitempty,uuid"`
+	Page      int    `form:"page" binding:"omitempty,min=1"`
+	PageSize  int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type InviteRedemptionDTO struct {
+	UserID     uuid.UUID `json:"user_id"`
+	Username   string    `json:"username"`
+	RedeemedAt time.Time `json:"redeemed_at"`
+}
+
+type InviteDTO struct {
+	ID          uuid.UUID              `json:"id"`
+	Code        string                 `json:"code"`
+	CreatedBy   uuid.UUID              `json:"created_by"`
+	Note        string                 `json:"note,omitempty"`
+	MaxUses     int                    `json:"max_uses"`
+	Uses        int                    `json:"uses"`
+	Status      string                 `json:"status"`
+	ExpiresAt   *time.Time             `json:"expires_at,omitempty"`
+	RevokedAt   *time.Time             `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	Redemptions []*InviteRedemptionDTO `json:"redemptions"`
+}
+
+type InviteListResponse struct {
+	Invites  []*InviteDTO `json:"invites"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+	Total    int64        `json:"total"`
+}
+
+type RegistrationInfoResponse struct {
+	// Mode is open, invite_only or closed.
+	Mode string `json:"mode"`
+}
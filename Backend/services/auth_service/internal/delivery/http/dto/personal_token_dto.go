@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreatePersonalTokenRequest struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=user:read user:write sessions:read sessions:write communities:read communities:write admin"`
+	// ExpiresAt is optional; tokens without it stay valid until revoked.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type PersonalTokenDTO struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Hint       string     `json:"hint"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedPersonalTokenResponse carries the token itself, which is only ever
+// shown once.
+type CreatedPersonalTokenResponse struct {
+	PersonalTokenDTO
+	Token string `json:"token"`
+}
+
+// TokenPrincipal is who a personal access token authenticates.
+type TokenPrincipal struct {
+	UserID  uuid.UUID
+	TokenID uuid.UUID
+	Scopes  []string
+	Roles   []string
+}
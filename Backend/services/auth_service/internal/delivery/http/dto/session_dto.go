@@ -11,15 +11,85 @@ type SessionResponseDTO struct {
 	UserID uuid.UUID `json:"user_id"`
     UserAgent  string `json:"user_agent"`
     IP         string `json:"ip"`
+    Browser    string `json:"browser"`
+    OS         string `json:"os"`
+    DeviceType string `json:"device_type"`
+    DeviceName string `json:"device_name,omitempty"`
     LastUsedAt time.Time `json:"last_used_at"`
 }
 
+// Session statuses reported in the login history.
+const (
+	SessionStatusActive  = "active"
+	SessionStatusRevoked = "revoked"
+	SessionStatusExpired = "expired"
+	SessionStatusIdle    = "idle"
+)
+
+// SessionHistoryQuery holds the query parameters of GET /sessions/history.
+// From and To are RFC 3339 timestamps bounding the session creation time.
+type SessionHistoryQuery struct {
+	Cursor string    `form:"cursor"`
+	Limit  int       `form:"limit" binding:"omitempty,min=1,max=100"`
+	From   time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To     time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type SessionHistoryItemDTO struct {
+	SessionResponseDTO
+	Status        string     `json:"status"`
+	CreatedAt     time.Time  `json:"created_at"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason string     `json:"revoked_reason,omitempty"`
+}
+
+type DeviceUsageDTO struct {
+	Browser    string    `json:"browser"`
+	OS         string    `json:"os"`
+	DeviceType string    `json:"device_type"`
+	DeviceName string    `json:"device_name,omitempty"`
+	Sessions   int       `json:"sessions"`
+	LastSeen   time.Time `json:"last_seen"`
+}
+
+type IPUsageDTO struct {
+	IP       string    `json:"ip"`
+	Sessions int       `json:"sessions"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// SessionHistorySummaryDTO covers every session in the requested date range,
+// not only the current page.
+type SessionHistorySummaryDTO struct {
+	DistinctDevices int              `json:"distinct_devices"`
+	DistinctIPs     int              `json:"distinct_ips"`
+	Devices         []DeviceUsageDTO `json:"devices"`
+	IPs             []IPUsageDTO     `json:"ips"`
+}
+
+type SessionHistoryResponse struct {
+	Sessions   []*SessionHistoryItemDTO `json:"sessions"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
+	Summary    SessionHistorySummaryDTO `json:"summary"`
+}
+
+// ClientInfo describes the device a request originates from. It is built by
+// the handlers from the request and stored on the session it creates or refreshes.
+type ClientInfo struct {
+	IP         string
+	UserAgent  string
+	DeviceName string
+}
+
 type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token"`
+	DeviceName   string `json:"device_name" binding:"max=64"`
 }
 
 type RefreshResponse struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"` // set when the session was renewed and the refresh token rotated
 }
 
 type MessageResponse struct {
@@ -1,6 +1,10 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type UserDto struct {
 	ID          uuid.UUID `json:"id"`
@@ -19,9 +23,49 @@ type RegisterUser struct {
 	Password    string `json:"password" binding:"required"`
 	PhoneNumber string `json:"phone_number"`
 	Country     string `json:"country"`
+	// InviteCode is required while registration is invite-only.
+	InviteCode string `json:"invite_code"`
+}
+
+// ResetPasswordRequest is posted as JSON or by the reset form.
+type ResetPasswordRequest struct {
+	Token       string `json:"token" form:"token" binding:"required"`
+	NewPassword string `json:"new_password" form:"new_password" binding:"required,min=8"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type DeleteAccountResponse struct {
+	Message  string    `json:"message"`
+	DeleteAt time.Time `json:"delete_at"`
+}
+
+type CancelDeletionRequest struct {
+	Identification string `json:"identification" binding:"required"`
+	Password       string `json:"password" binding:"required"`
+}
+
+// DenyLoginRequest is posted as JSON or by the "this wasn't me" confirmation page.
+type DenyLoginRequest struct {
+	Token string `json:"token" form:"token" binding:"required"`
 }
 
 type LoginRequest struct {
 	Identification string `json:"identification" binding:"required"`
 	Password       string `json:"password" binding:"required"`
+	DeviceName     string `json:"device_name" binding:"max=64"`
+	// UseCookies asks for the tokens as HttpOnly cookies instead of in the body (web client).
+	UseCookies bool `json:"use_cookies"`
+}
+
+// AccountStatusResponse is returned with 403 Forbidden when a suspended,
+// banned or deactivated account is refused. Code is account_<status>.
+type AccountStatusResponse struct {
+	Message string     `json:"message"`
+	Error   string     `json:"error"`
+	Code    string     `json:"code"`
+	Reason  string     `json:"reason,omitempty"`
+	Until   *time.Time `json:"until,omitempty"`
 }
@@ -0,0 +1,414 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"auth/internal/delivery/http/dto"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AdminHandler defines the HTTP handlers of the admin API.
+type AdminHandler struct {
+	usecase        usecaseinterfaces.AdminUsecaseInterface
+	sessionUsecase usecaseinterfaces.SessionUsecaseInterface
+}
+
+// NewAdminHandler creates a new instance of AdminHandler.
+func NewAdminHandler(usecase usecaseinterfaces.AdminUsecaseInterface, sessionUsecase usecaseinterfaces.SessionUsecaseInterface) *AdminHandler {
+	return &AdminHandler{usecase: usecase, sessionUsecase: sessionUsecase}
+}
+
+// ListUsers godoc
+// @Summary      List and search users
+// @Description  Pages through all accounts, newest first. q matches email, username, full name and phone number.
+// @Tags         admin
+// @Produce      json
+// @Param        q          query     string  false  "Search text"
+// @Param        role       query     string  false  "Only users with this role"
+// @Param        status     query     string  false  "Only users with this account status"
+// @Param        verified   query     bool    false  "Only verified or unverified users"
+// @Param        page       query     int     false  "Page number (default 1)"
+// @Param        page_size  query     int     false  "Page size (1-100, default 20)"
+// @Success      200        {object}  dto.AdminUserListResponse
+// @Failure      400        {object}  dto.MessageResponse
+// @Failure      401        {object}  dto.MessageResponse
+// @Failure      403        {object}  dto.MessageResponse
+// @Failure      500        {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /admin/users [get]
+func (h *AdminHandler) ListUsers(ctx *gin.Context) {
+	var query dto.AdminUserQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid query parameters", "error": err.Error()})
+		return
+	}
+
+	users, err := h.usecase.ListUsers(ctx.Request.Context(), &query)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to list users", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, users)
+}
+
+// GetUser godoc
+// @Summary      Get a user
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  dto.AdminUserDTO
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      403  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /admin/users/{id} [get]
+func (h *AdminHandler) GetUser(ctx *gin.Context) {
+	userID, ok := userIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	user, err := h.usecase.GetUser(ctx.Request.Context(), userID)
+	if err != nil {
+		writeAdminError(ctx, err, "Failed to retrieve user")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+// ListUserSessions godoc
+// @Summary      Get a user's sessions
+// @Description  Pages through all sessions of the user, including revoked and expired ones, newest first.
+// @Tags         admin
+// @Produce      json
+// @Param        id      path      string  true   "User ID"
+// @Param        cursor  query     string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        limit   query     int     false  "Page size (1-100, default 20)"
+// @Param        from    query     string  false  "Only sessions created at or after this RFC 3339 time"
+// @Param        to      query     string  false  "Only sessions created before this RFC 3339 time"
+// @Success      200     {object}  dto.SessionHistoryResponse
+// @Failure      400     {object}  dto.MessageResponse
+// @Failure      401     {object}  dto.MessageResponse
+// @Failure      403     {object}  dto.MessageResponse
+// @Failure      404     {object}  dto.MessageResponse
+// @Failure      500     {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /admin/users/{id}/sessions [get]
+func (h *AdminHandler) ListUserSessions(ctx *gin.Context) {
+	userID, ok := userIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var query dto.SessionHistoryQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid query parameters", "error": err.Error()})
+		return
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid query parameters", "error": "from must be before to"})
+		return
+	}
+
+	if _, err := h.usecase.GetUser(ctx.Request.Context(), userID); err != nil {
+		writeAdminError(ctx, err, "Failed to retrieve sessions")
+		return
+	}
+	history, err := h.sessionUsecase.ListSessionHistory(ctx.Request.Context(), userID, &query)
+	if err != nil {
+		if err.Error() == "invalid cursor" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid cursor", "error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to retrieve sessions", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
+
+// SuspendUser godoc
+// @Summary      Suspend a user
+// @Description  Blocks the account from logging in and revokes all of its sessions. With until the suspension lifts automatically at that time.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                  true  "User ID"
+// @Param        request  body      dto.SuspendUserRequest  true  "Reason for the suspension"
+// @Success      200      {object}  dto.MessageResponse
+// @Failure      400      {object}  dto.MessageResponse
+// @Failure      401      {object}  dto.MessageResponse
+// @Failure      403      {object}  dto.MessageResponse
+// @Failure      404      {object}  dto.MessageResponse
+// @Failure      409      {object}  dto.MessageResponse
+// @Failure      500      {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /admin/users/{id}/suspend [post]
+func (h *AdminHandler) SuspendUser(ctx *gin.Context) {
+	actor, userID, ok := adminTarget(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.SuspendUserRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request body", "error": err.Error()})
+		return
+	}
+
+	if err := h.usecase.SuspendUser(ctx.Request.Context(), actor, userID, request.Reason, request.Until); err != nil {
+		writeAdminError(ctx, err, "Failed to suspend user")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "User suspended"})
+}
+
+// UnsuspendUser godoc
+// @Summary      Lift a user's suspension
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  dto.MessageResponse
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      403  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      409  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /admin/users/{id}/unsuspend [post]
+func (h *AdminHandler) UnsuspendUser(ctx *gin.Context) {
+	actor, userID, ok := adminTarget(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.UnsuspendUser(ctx.Request.Context(), actor, userID); err != nil {
+		writeAdminError(ctx, err, "Failed to lift suspension")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Suspension lifted"})
+}
+
+// ChangeStatus godoc
+// @Summary      Change a user's account status
+// @Description  Sets the account to active, suspended, banned or deactivated. Any status other than active revokes all of the account's sessions. Only suspensions may have an expiry.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                   true  "User ID"
+// @Param        request  body      dto.ChangeStatusRequest  true  "New status"
+// @Success      200      {object}  dto.MessageResponse
+// @Failure      400      {object}  dto.MessageResponse
+// @Failure      401      {object}  dto.MessageResponse
+// @Failure      403      {object}  dto.MessageResponse
+// @Failure      404      {object}  dto.MessageResponse
+// @Failure      409      {object}  dto.MessageResponse
+// @Failure      500      {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /admin/users/{id}/status [put]
+func (h *AdminHandler) ChangeStatus(ctx *gin.Context) {
+	actor, userID, ok := adminTarget(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.ChangeStatusRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request body", "error": err.Error()})
+		return
+	}
+
+	if err := h.usecase.ChangeStatus(ctx.Request.Context(), actor, userID, request.Status, request.Reason, request.Until); err != nil {
+		writeAdminError(ctx, err, "Failed to change account status")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Account status changed"})
+}
+
+// VerifyUser godoc
+// @Summary      Force-verify a user
+// @Description  Marks the account as verified without the usual verification flow.
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  dto.MessageResponse
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      403  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      409  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /admin/users/{id}/verify [post]
+func (h *AdminHandler) VerifyUser(ctx *gin.Context) {
+	actor, userID, ok := adminTarget(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.VerifyUser(ctx.Request.Context(), actor, userID); err != nil {
+		writeAdminError(ctx, err, "Failed to verify user")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "User verified"})
+}
+
+// ChangeRole godoc
+// @Summary      Change a user's role
+// @Description  Gives the user a new role and revokes their sessions so the change applies immediately.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                 true  "User ID"
+// @Param        request  body      dto.ChangeRoleRequest  true  "New role"
+// @Success      200      {object}  dto.MessageResponse
+// @Failure      400      {object}  dto.MessageResponse
+// @Failure      401      {object}  dto.MessageResponse
+// @Failure      403      {object}  dto.MessageResponse
+// @Failure      404      {object}  dto.MessageResponse
+// @Failure      500      {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /admin/users/{id}/role [put]
+func (h *AdminHandler) ChangeRole(ctx *gin.Context) {
+	actor, userID, ok := adminTarget(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.ChangeRoleRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request body", "error": err.Error()})
+		return
+	}
+
+	if err := h.usecase.ChangeRole(ctx.Request.Context(), actor, userID, request.Role); err != nil {
+		writeAdminError(ctx, err, "Failed to change role")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Role changed"})
+}
+
+// ForceLogout godoc
+// @Summary      Sign a user out everywhere
+// @Description  Revokes every session of the user.
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  dto.MessageResponse
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      403  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /admin/users/{id}/logout [post]
+func (h *AdminHandler) ForceLogout(ctx *gin.Context) {
+	actor, userID, ok := adminTarget(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.ForceLogout(ctx.Request.Context(), actor, userID); err != nil {
+		writeAdminError(ctx, err, "Failed to log out user")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "User logged out from all devices"})
+}
+
+// ListAuditLogs godoc
+// @Summary      Get the admin audit trail
+// @Description  Pages through recorded administrative actions, newest first.
+// @Tags         admin
+// @Produce      json
+// @Param        actor_id   query     string  false  "Only actions by this administrator"
+// @Param        target_id  query     string  false  "Only actions on this user"
+// @Param        action     query     string  false  "Only this action, e.g. user.suspend"
+// @Param        page       query     int     false  "Page number (default 1)"
+// @Param        page_size  query     int     false  "Page size (1-100, default 20)"
+// @Success      200        {object}  dto.AuditLogListResponse
+// @Failure      400        {object}  dto.MessageResponse
+// @Failure      401        {object}  dto.MessageResponse
+// @Failure      403        {object}  dto.MessageResponse
+// @Failure      500        {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /admin/audit-logs [get]
+func (h *AdminHandler) ListAuditLogs(ctx *gin.Context) {
+	var query dto.AuditLogQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid query parameters", "error": err.Error()})
+		return
+	}
+
+	entries, err := h.usecase.ListAuditLogs(ctx.Request.Context(), &query)
+	if err != nil {
+		writeAdminError(ctx, err, "Failed to retrieve audit logs")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
+
+// userIDParam parses the :id path parameter, responding with 400 when it is invalid.
+func userIDParam(ctx *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid user ID", "error": err.Error()})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// adminTarget returns the administrator making the request and the user it targets.
+func adminTarget(ctx *gin.Context) (*dto.AdminActor, uuid.UUID, bool) {
+	actorID, ok := ctx.Get("user_id")
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "User ID not found in context"})
+		return nil, uuid.Nil, false
+	}
+	parsedActorID, ok := actorID.(uuid.UUID)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "User ID in context is not a valid UUID"})
+		return nil, uuid.Nil, false
+	}
+
+	userID, ok := userIDParam(ctx)
+	if !ok {
+		return nil, uuid.Nil, false
+	}
+	return &dto.AdminActor{UserID: parsedActorID, IP: ctx.ClientIP()}, userID, true
+}
+
+// writeAdminError maps admin usecase errors to responses, using message for
+// unexpected failures.
+func writeAdminError(ctx *gin.Context, err error, message string) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "User not found", "error": err.Error()})
+		return
+	}
+	switch err.Error() {
+	case "cannot change your own account", "invalid role", "invalid status", "only suspensions can expire",
+		"expiry must be in the future", "invalid actor id", "invalid target id":
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": message, "error": err.Error()})
+	case "user is already active", "user is already banned", "user is already deactivated",
+		"user is not suspended", "user is already verified", "account has been deleted":
+		ctx.JSON(http.StatusConflict, gin.H{"message": message, "error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": message, "error": err.Error()})
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth/internal/delivery/http/dto"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthzHandler answers permission checks for other services.
+type AuthzHandler struct {
+	usecase usecaseinterfaces.AuthzUsecaseInterface
+}
+
+// NewAuthzHandler creates a new instance of AuthzHandler.
+func NewAuthzHandler(usecase usecaseinterfaces.AuthzUsecaseInterface) *AuthzHandler {
+	return &AuthzHandler{usecase: usecase}
+}
+
+// Check godoc
+// @Summary      Check a permission
+// @Description  Decides whether a user may perform an action, optionally within a community. Platform roles are considered first, then the user's role in the community. Users whose account is not active are always denied. Meant for other services, which authenticate with the X-Service-Token header.
+// @Tags         authz
+// @Accept       json
+// @Produce      json
+// @Param        X-Service-Token  header    string                 true  "Shared service token"
+// @Param        request          body      dto.AuthzCheckRequest  true  "Subject, action and resource"
+// @Success      200              {object}  dto.AuthzCheckResponse
+// @Failure      400              {object}  dto.MessageResponse
+// @Failure      401              {object}  dto.MessageResponse
+// @Failure      500              {object}  dto.MessageResponse
+// @Failure      503              {object}  dto.MessageResponse
+// @Router       /authz/check [post]
+func (h *AuthzHandler) Check(ctx *gin.Context) {
+	var request dto.AuthzCheckRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request body", "error": err.Error()})
+		return
+	}
+
+	decision, err := h.usecase.Check(ctx.Request.Context(), &request)
+	if err != nil {
+		if err.Error() == "unknown action" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Unknown action", "error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to check permission", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, decision)
+}
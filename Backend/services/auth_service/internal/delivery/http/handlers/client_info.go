@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"auth/internal/delivery/http/dto"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxUserAgentLength caps how much of the User-Agent header is stored per session.
+const maxUserAgentLength = 512
+
+// clientInfo collects the caller's device metadata. The IP is resolved by gin,
+// which only honours forwarding headers from the router's trusted proxies.
+func clientInfo(ctx *gin.Context, deviceName string) *dto.ClientInfo {
+	userAgent := truncateUTF8(ctx.Request.UserAgent(), maxUserAgentLength)
+	return &dto.ClientInfo{
+		IP:         ctx.ClientIP(),
+		UserAgent:  userAgent,
+		DeviceName: deviceName,
+	}
+}
+
+// truncateUTF8 cuts s to at most max bytes without splitting a character,
+// and drops any invalid UTF-8, which Postgres would refuse to store as text.
+func truncateUTF8(s string, max int) string {
+	if len(s) > max {
+		cut := max
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		s = s[:cut]
+	}
+	return strings.ToValidUTF8(s, "")
+}
@@ -0,0 +1,639 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"auth/internal/delivery/http/dto"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CommunityHandler defines the HTTP handlers for communities, their members,
+// invitations and join requests.
+type CommunityHandler struct {
+	usecase usecaseinterfaces.CommunityUsecaseInterface
+}
+
+// NewCommunityHandler creates a new instance of CommunityHandler.
+func NewCommunityHandler(usecase usecaseinterfaces.CommunityUsecaseInterface) *CommunityHandler {
+	return &CommunityHandler{usecase: usecase}
+}
+
+// CreateCommunity godoc
+// @Summary      Create a community
+// @Description  Creates a community with the authenticated user as its owner.
+// @Tags         communities
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.CreateCommunityRequest  true  "Name, slug and description"
+// @Success      201      {object}  dto.CommunityDTO
+// @Failure      400      {object}  dto.MessageResponse
+// @Failure      401      {object}  dto.MessageResponse
+// @Failure      409      {object}  dto.MessageResponse
+// @Failure      500      {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /communities [post]
+func (h *CommunityHandler) CreateCommunity(ctx *gin.Context) {
+	userID, ok := contextUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.CreateCommunityRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request body", "error": err.Error()})
+		return
+	}
+
+	community, err := h.usecase.CreateCommunity(ctx.Request.Context(), userID, &request)
+	if err != nil {
+		writeCommunityError(ctx, err, "Failed to create community")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, community)
+}
+
+// GetCommunity godoc
+// @Summary      Get a community
+// @Tags         communities
+// @Produce      json
+// @Param        id   path      string  true  "Community ID"
+// @Success      200  {object}  dto.CommunityDTO
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /communities/{id} [get]
+func (h *CommunityHandler) GetCommunity(ctx *gin.Context) {
+	communityID, ok := uuidParam(ctx, "id", "community")
+	if !ok {
+		return
+	}
+
+	community, err := h.usecase.GetCommunity(ctx.Request.Context(), communityID)
+	if err != nil {
+		writeCommunityError(ctx, err, "Failed to retrieve community")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, community)
+}
+
+// DeleteCommunity godoc
+// @Summary      Delete a community
+// @Description  Deletes the community with its memberships, invitations and join requests. Only owners may do this.
+// @Tags         communities
+// @Produce      json
+// @Param        id   path      string  true  "Community ID"
+// @Success      200  {object}  dto.MessageResponse
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      403  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /communities/{id} [delete]
+func (h *CommunityHandler) DeleteCommunity(ctx *gin.Context) {
+	userID, communityID, ok := communityRequest(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.DeleteCommunity(ctx.Request.Context(), userID, communityID); err != nil {
+		writeCommunityError(ctx, err, "Failed to delete community")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Community deleted"})
+}
+
+// ListMyCommunities godoc
+// @Summary      List your communities
+// @Description  Lists the communities the authenticated user belongs to, with their role in each.
+// @Tags         communities
+// @Produce      json
+// @Success      200  {array}   dto.MyCommunityDTO
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /user/communities [get]
+func (h *CommunityHandler) ListMyCommunities(ctx *gin.Context) {
+	userID, ok := contextUserID(ctx)
+	if !ok {
+		return
+	}
+
+	communities, err := h.usecase.ListMyCommunities(ctx.Request.Context(), userID)
+	if err != nil {
+		writeCommunityError(ctx, err, "Failed to retrieve communities")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, communities)
+}
+
+// ListMembers godoc
+// @Summary      List community members
+// @Description  Pages through the members of a community. Only members may see it.
+// @Tags         communities
+// @Produce      json
+// @Param        id         path      string  true   "Community ID"
+// @Param        page       query     int     false  "Page number (default 1)"
+// @Param        page_size  query     int     false  "Page size (1-100, default 20)"
+// @Success      200        {object}  dto.CommunityMemberListResponse
+// @Failure      400        {object}  dto.MessageResponse
+// @Failure      401        {object}  dto.MessageResponse
+// @Failure      403        {object}  dto.MessageResponse
+// @Failure      404        {object}  dto.MessageResponse
+// @Failure      500        {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /communities/{id}/members [get]
+func (h *CommunityHandler) ListMembers(ctx *gin.Context) {
+	userID, communityID, ok := communityRequest(ctx)
+	if !ok {
+		return
+	}
+
+	var query dto.CommunityMemberQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid query parameters", "error": err.Error()})
+		return
+	}
+
+	members, err := h.usecase.ListMembers(ctx.Request.Context(), userID, communityID, &query)
+	if err != nil {
+		writeCommunityError(ctx, err, "Failed to retrieve members")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, members)
+}
+
+// ChangeMemberRole godoc
+// @Summary      Change a member's role
+// @Description  Makes a member an owner, moderator or member. Only owners may do this, and the last owner cannot be demoted.
+// @Tags         communities
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                       true  "Community ID"
+// @Param        userId   path      string                       true  "Member's user ID"
+// @Param        request  body      dto.ChangeMemberRoleRequest  true  "New role"
+// @Success      200      {object}  dto.MessageResponse
+// @Failure      400      {object}  dto.MessageResponse
+// @Failure      401      {object}  dto.MessageResponse
+// @Failure      403      {object}  dto.MessageResponse
+// @Failure      404      {object}  dto.MessageResponse
+// @Failure      409      {object}  dto.MessageResponse
+// @Failure      500      {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /communities/{id}/members/{userId}/role [put]
+func (h *CommunityHandler) ChangeMemberRole(ctx *gin.Context) {
+	userID, communityID, ok := communityRequest(ctx)
+	if !ok {
+		return
+	}
+	memberID, ok := uuidParam(ctx, "userId", "user")
+	if !ok {
+		return
+	}
+
+	var request dto.ChangeMemberRoleRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request body", "error": err.Error()})
+		return
+	}
+
+	if err := h.usecase.ChangeMemberRole(ctx.Request.Context(), userID, communityID, memberID, request.Role); err != nil {
+		writeCommunityError(ctx, err, "Failed to change member role")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Member role changed"})
+}
+
+// RemoveMember godoc
+// @Summary      Remove a member
+// @Description  Removes someone from the community. Moderators may only remove members; owners may remove anyone but the last owner.
+// @Tags         communities
+// @Produce      json
+// @Param        id      path      string  true  "Community ID"
+// @Param        userId  path      string  true  "Member's user ID"
+// @Success      200     {object}  dto.MessageResponse
+// @Failure      400     {object}  dto.MessageResponse
+// @Failure      401     {object}  dto.MessageResponse
+// @Failure      403     {object}  dto.MessageResponse
+// @Failure      404     {object}  dto.MessageResponse
+// @Failure      409     {object}  dto.MessageResponse
+// @Failure      500     {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /communities/{id}/members/{userId} [delete]
+func (h *CommunityHandler) RemoveMember(ctx *gin.Context) {
+	userID, communityID, ok := communityRequest(ctx)
+	if !ok {
+		return
+	}
+	memberID, ok := uuidParam(ctx, "userId", "user")
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.RemoveMember(ctx.Request.Context(), userID, communityID, memberID); err != nil {
+		writeCommunityError(ctx, err, "Failed to remove member")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Member removed"})
+}
+
+// Leave godoc
+// @Summary      Leave a community
+// @Description  Removes the authenticated user from the community. The last owner cannot leave.
+// @Tags         communities
+// @Produce      json
+// @Param        id   path      string  true  "Community ID"
+// @Success      200  {object}  dto.MessageResponse
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      403  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      409  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /communities/{id}/leave [post]
+func (h *CommunityHandler) Leave(ctx *gin.Context) {
+	userID, communityID, ok := communityRequest(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Leave(ctx.Request.Context(), userID, communityID); err != nil {
+		writeCommunityError(ctx, err, "Failed to leave community")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Left community"})
+}
+
+// Invite godoc
+// @Summary      Invite a user
+// @Description  Invites an existing user, by email or username, to join the community. Owners and moderators may invite members; only owners may invite moderators. The invitee is notified.
+// @Tags         communities
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                       true  "Community ID"
+// @Param        request  body      dto.CreateInvitationRequest  true  "Invitee and role"
+// @Success      201      {object}  dto.InvitationDTO
+// @Failure      400      {object}  dto.MessageResponse
+// @Failure      401      {object}  dto.MessageResponse
+// @Failure      403      {object}  dto.MessageResponse
+// @Failure      404      {object}  dto.MessageResponse
+// @Failure      409      {object}  dto.MessageResponse
+// @Failure      500      {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /communities/{id}/invitations [post]
+func (h *CommunityHandler) Invite(ctx *gin.Context) {
+	userID, communityID, ok := communityRequest(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.CreateInvitationRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request body", "error": err.Error()})
+		return
+	}
+
+	invitation, err := h.usecase.Invite(ctx.Request.Context(), userID, communityID, &request)
+	if err != nil {
+		writeCommunityError(ctx, err, "Failed to invite user")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, invitation)
+}
+
+// ListInvitations godoc
+// @Summary      List pending invitations
+// @Description  Lists the community's invitations that can still be accepted. Only owners and moderators may see them.
+// @Tags         communities
+// @Produce      json
+// @Param        id   path      string  true  "Community ID"
+// @Success      200  {array}   dto.InvitationDTO
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      403  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /communities/{id}/invitations [get]
+func (h *CommunityHandler) ListInvitations(ctx *gin.Context) {
+	userID, communityID, ok := communityRequest(ctx)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.usecase.ListInvitations(ctx.Request.Context(), userID, communityID)
+	if err != nil {
+		writeCommunityError(ctx, err, "Failed to retrieve invitations")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invitations)
+}
+
+// RevokeInvitation godoc
+// @Summary      Revoke an invitation
+// @Tags         communities
+// @Produce      json
+// @Param        id            path      string  true  "Community ID"
+// @Param        invitationId  path      string  true  "Invitation ID"
+// @Success      200           {object}  dto.MessageResponse
+// @Failure      400           {object}  dto.MessageResponse
+// @Failure      401           {object}  dto.MessageResponse
+// @Failure      403           {object}  dto.MessageResponse
+// @Failure      404           {object}  dto.MessageResponse
+// @Failure      409           {object}  dto.MessageResponse
+// @Failure      500           {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /communities/{id}/invitations/{invitationId} [delete]
+func (h *CommunityHandler) RevokeInvitation(ctx *gin.Context) {
+	userID, communityID, ok := communityRequest(ctx)
+	if !ok {
+		return
+	}
+	invitationID, ok := uuidParam(ctx, "invitationId", "invitation")
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.RevokeInvitation(ctx.Request.Context(), userID, communityID, invitationID); err != nil {
+		writeCommunityError(ctx, err, "Failed to revoke invitation")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Invitation revoked"})
+}
+
+// ListMyInvitations godoc
+// @Summary      List your invitations
+// @Description  Lists the pending invitations addressed to the authenticated user.
+// @Tags         communities
+// @Produce      json
+// @Success      200  {array}   dto.InvitationDTO
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /user/invitations [get]
+func (h *CommunityHandler) ListMyInvitations(ctx *gin.Context) {
+	userID, ok := contextUserID(ctx)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.usecase.ListMyInvitations(ctx.Request.Context(), userID)
+	if err != nil {
+		writeCommunityError(ctx, err, "Failed to retrieve invitations")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invitations)
+}
+
+// AcceptInvitation godoc
+// @Summary      Accept an invitation
+// @Description  Joins the community with the role the invitation offers.
+// @Tags         communities
+// @Produce      json
+// @Param        id   path      string  true  "Invitation ID"
+// @Success      200  {object}  dto.MessageResponse
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      409  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /user/invitations/{id}/accept [post]
+func (h *CommunityHandler) AcceptInvitation(ctx *gin.Context) {
+	userID, ok := contextUserID(ctx)
+	if !ok {
+		return
+	}
+	invitationID, ok := uuidParam(ctx, "id", "invitation")
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.AcceptInvitation(ctx.Request.Context(), userID, invitationID); err != nil {
+		writeCommunityError(ctx, err, "Failed to accept invitation")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Invitation accepted"})
+}
+
+// DeclineInvitation godoc
+// @Summary      Decline an invitation
+// @Tags         communities
+// @Produce      json
+// @Param        id   path      string  true  "Invitation ID"
+// @Success      200  {object}  dto.MessageResponse
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      409  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /user/invitations/{id}/decline [post]
+func (h *CommunityHandler) DeclineInvitation(ctx *gin.Context) {
+	userID, ok := contextUserID(ctx)
+	if !ok {
+		return
+	}
+	invitationID, ok := uuidParam(ctx, "id", "invitation")
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.DeclineInvitation(ctx.Request.Context(), userID, invitationID); err != nil {
+		writeCommunityError(ctx, err, "Failed to decline invitation")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Invitation declined"})
+}
+
+// RequestToJoin godoc
+// @Summary      Ask to join a community
+// @Description  Files a join request for the owners and moderators of the community to decide on.
+// @Tags         communities
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                        true  "Community ID"
+// @Param        request  body      dto.CreateJoinRequestRequest  true  "Optional message"
+// @Success      201      {object}  dto.JoinRequestDTO
+// @Failure      400      {object}  dto.MessageResponse
+// @Failure      401      {object}  dto.MessageResponse
+// @Failure      404      {object}  dto.MessageResponse
+// @Failure      409      {object}  dto.MessageResponse
+// @Failure      500      {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /communities/{id}/join-requests [post]
+func (h *CommunityHandler) RequestToJoin(ctx *gin.Context) {
+	userID, communityID, ok := communityRequest(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.CreateJoinRequestRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request body", "error": err.Error()})
+		return
+	}
+
+	joinRequest, err := h.usecase.RequestToJoin(ctx.Request.Context(), userID, communityID, &request)
+	if err != nil {
+		writeCommunityError(ctx, err, "Failed to request to join")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, joinRequest)
+}
+
+// ListJoinRequests godoc
+// @Summary      List pending join requests
+// @Description  Only owners and moderators may see them.
+// @Tags         communities
+// @Produce      json
+// @Param        id   path      string  true  "Community ID"
+// @Success      200  {array}   dto.JoinRequestDTO
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      403  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /communities/{id}/join-requests [get]
+func (h *CommunityHandler) ListJoinRequests(ctx *gin.Context) {
+	userID, communityID, ok := communityRequest(ctx)
+	if !ok {
+		return
+	}
+
+	requests, err := h.usecase.ListJoinRequests(ctx.Request.Context(), userID, communityID)
+	if err != nil {
+		writeCommunityError(ctx, err, "Failed to retrieve join requests")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, requests)
+}
+
+// ApproveJoinRequest godoc
+// @Summary      Approve a join request
+// @Description  Adds the requester to the community as a member.
+// @Tags         communities
+// @Produce      json
+// @Param        id         path      string  true  "Community ID"
+// @Param        requestId  path      string  true  "Join request ID"
+// @Success      200        {object}  dto.MessageResponse
+// @Failure      400        {object}  dto.MessageResponse
+// @Failure      401        {object}  dto.MessageResponse
+// @Failure      403        {object}  dto.MessageResponse
+// @Failure      404        {object}  dto.MessageResponse
+// @Failure      409        {object}  dto.MessageResponse
+// @Failure      500        {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /communities/{id}/join-requests/{requestId}/approve [post]
+func (h *CommunityHandler) ApproveJoinRequest(ctx *gin.Context) {
+	h.decideJoinRequest(ctx, h.usecase.ApproveJoinRequest, "Join request approved", "Failed to approve join request")
+}
+
+// RejectJoinRequest godoc
+// @Summary      Reject a join request
+// @Tags         communities
+// @Produce      json
+// @Param        id         path      string  true  "Community ID"
+// @Param        requestId  path      string  true  "Join request ID"
+// @Success      200        {object}  dto.MessageResponse
+// @Failure      400        {object}  dto.MessageResponse
+// @Failure      401        {object}  dto.MessageResponse
+// @Failure      403        {object}  dto.MessageResponse
+// @Failure      404        {object}  dto.MessageResponse
+// @Failure      409        {object}  dto.MessageResponse
+// @Failure      500        {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /communities/{id}/join-requests/{requestId}/reject [post]
+func (h *CommunityHandler) RejectJoinRequest(ctx *gin.Context) {
+	h.decideJoinRequest(ctx, h.usecase.RejectJoinRequest, "Join request rejected", "Failed to reject join request")
+}
+
+func (h *CommunityHandler) decideJoinRequest(ctx *gin.Context, decide func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error, success string, failure string) {
+	userID, communityID, ok := communityRequest(ctx)
+	if !ok {
+		return
+	}
+	requestID, ok := uuidParam(ctx, "requestId", "join request")
+	if !ok {
+		return
+	}
+
+	if err := decide(ctx.Request.Context(), userID, communityID, requestID); err != nil {
+		writeCommunityError(ctx, err, failure)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": success})
+}
+
+// communityRequest returns the authenticated user and the community in the
+// :id path parameter.
+func communityRequest(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := contextUserID(ctx)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	communityID, ok := uuidParam(ctx, "id", "community")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, communityID, true
+}
+
+// uuidParam parses the path parameter name, answering with 400 when it is not
+// a UUID. label names the resource in the error message.
+func uuidParam(ctx *gin.Context, name string, label string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param(name))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid " + label + " ID", "error": err.Error()})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func writeCommunityError(ctx *gin.Context, err error, message string) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Not found", "error": err.Error()})
+		return
+	}
+	switch err.Error() {
+	case "invalid slug":
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Slugs may only contain lowercase letters, digits and single hyphens", "error": err.Error()})
+	case "user not found":
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "User not found", "error": err.Error()})
+	case "not a community member", "insufficient community role":
+		ctx.JSON(http.StatusForbidden, gin.H{"message": message, "error": err.Error()})
+	case "slug already taken", "already a member", "invitation already pending", "join request already pending",
+		"community must keep an owner", "invitation is no longer pending", "join request is no longer pending":
+		ctx.JSON(http.StatusConflict, gin.H{"message": message, "error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": message, "error": err.Error()})
+	}
+}
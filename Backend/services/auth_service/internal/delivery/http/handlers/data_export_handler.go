@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"auth/internal/delivery/http/dto"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DataExportHandler defines the HTTP handlers for personal data exports.
+type DataExportHandler struct {
+	usecase usecaseinterfaces.DataExportUsecaseInterface
+}
+
+// NewDataExportHandler creates a new instance of DataExportHandler.
+func NewDataExportHandler(usecase usecaseinterfaces.DataExportUsecaseInterface) *DataExportHandler {
+	return &DataExportHandler{usecase: usecase}
+}
+
+// RequestExport godoc
+// @Summary      Request a copy of your personal data
+// @Description  Starts building a JSON archive of the profile, consents, sessions and security events of the authenticated user. The user is notified with a download link once it is ready.
+// @Tags         user
+// @Produce      json
+// @Success      202  {object}  dto.DataExportDTO
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      409  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /user/me/export [post]
+func (h *DataExportHandler) RequestExport(ctx *gin.Context) {
+	userID, ok := ctx.Get("user_id")
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "User ID not found in context"})
+		return
+	}
+
+	parsedID, ok := userID.(uuid.UUID)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "User ID in context is not a valid UUID"})
+		return
+	}
+
+	export, err := h.usecase.RequestExport(ctx.Request.Context(), parsedID)
+	if err != nil {
+		if err.Error() == "export already in progress" {
+			ctx.JSON(http.StatusConflict, gin.H{"message": "An export is already being prepared", "error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to request data export", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, export)
+}
+
+// GetExport godoc
+// @Summary      Get the state of a data export
+// @Description  Reports whether the export is ready. Ready exports include a signed download link.
+// @Tags         user
+// @Produce      json
+// @Param        id   path      string  true  "Export ID"
+// @Success      200  {object}  dto.DataExportDTO
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /user/me/export/{id} [get]
+func (h *DataExportHandler) GetExport(ctx *gin.Context) {
+	userID, ok := ctx.Get("user_id")
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "User ID not found in context"})
+		return
+	}
+
+	parsedID, ok := userID.(uuid.UUID)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "User ID in context is not a valid UUID"})
+		return
+	}
+
+	exportID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid export ID", "error": err.Error()})
+		return
+	}
+
+	export, err := h.usecase.GetExport(ctx.Request.Context(), parsedID, exportID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Export not found", "error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to retrieve data export", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, export)
+}
+
+// Download godoc
+// @Summary      Download a data export
+// @Description  Target of the signed link sent when an export is ready. Returns the JSON archive as an attachment.
+// @Tags         user
+// @Produce      json
+// @Param        token  query     string  true  "Signed download token"
+// @Success      200    {object}  dto.PersonalDataArchive
+// @Failure      400    {object}  dto.MessageResponse
+// @Failure      410    {object}  dto.MessageResponse
+// @Failure      500    {object}  dto.MessageResponse
+// @Router       /exports/download [get]
+func (h *DataExportHandler) Download(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		ctx.JSON(http.StatusBadRequest, dto.MessageResponse{Message: "Token is required"})
+		return
+	}
+
+	path, filename, err := h.usecase.OpenDownload(ctx.Request.Context(), token)
+	if err != nil {
+		if err.Error() == "invalid or expired link" {
+			ctx.JSON(http.StatusGone, dto.MessageResponse{Message: "This download link is invalid or has expired", Error: err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, dto.MessageResponse{Message: "Failed to download data export", Error: err.Error()})
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.FileAttachment(path, filename)
+}
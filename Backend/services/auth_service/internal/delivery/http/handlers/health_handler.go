@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"auth/internal/delivery/http/dto"
+	"auth/internal/infrastructure/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadinessChecker reports whether the service's dependencies are reachable.
+type ReadinessChecker interface {
+	Readiness(ctx context.Context) *health.Report
+}
+
+// HealthHandler serves the liveness and readiness probes.
+type HealthHandler struct {
+	checker ReadinessChecker
+}
+
+// NewHealthHandler creates a new instance of HealthHandler.
+func NewHealthHandler(checker ReadinessChecker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// Liveness answers as long as the process can serve requests.
+func (h *HealthHandler) Liveness(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, dto.LivenessResponse{Status: health.StatusOK})
+}
+
+// Readiness answers 200 when Postgres and Redis are reachable and the schema
+// is up to date, and 503 otherwise or once shutdown has begun.
+func (h *HealthHandler) Readiness(ctx *gin.Context) {
+	report := h.checker.Readiness(ctx.Request.Context())
+	status := http.StatusOK
+	if report.Status != health.StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, toReadinessResponse(report))
+}
+
+func toReadinessResponse(report *health.Report) *dto.ReadinessResponse {
+	checks := make(map[string]dto.DependencyStatus, len(report.Checks))
+	for name, check := range report.Checks {
+		checks[name] = dto.DependencyStatus{Status: check.Status}
+	}
+	return &dto.ReadinessResponse{
+		Status:           report.Status,
+		MigrationVersion: report.MigrationVersion,
+		LatestMigration:  report.LatestMigration,
+		Checks:           checks,
+	}
+}
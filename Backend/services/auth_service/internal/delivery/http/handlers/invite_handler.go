@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"auth/internal/delivery/http/dto"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// InviteHandler defines the HTTP handlers for invite codes.
+type InviteHandler struct {
+	usecase usecaseinterfaces.InviteUsecaseInterface
+}
+
+// NewInviteHandler creates a new instance of InviteHandler.
+func NewInviteHandler(usecase usecaseinterfaces.InviteUsecaseInterface) *InviteHandler {
+	return &InviteHandler{usecase: usecase}
+}
+
+// CreateInvite godoc
+// @Summary      Create an invite code
+// @Description  Issues a code that lets someone register while registration is invite-only. Regular users are limited in how many active codes they hold, how many registrations a code allows and how long it lasts; administrators are not, and may set max_uses to 0 for unlimited.
+// @Tags         invites
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.CreateInviteRequest  true  "Optional use limit, expiry and note"
+// @Success      201      {object}  dto.InviteDTO
+// @Failure      400      {object}  dto.MessageResponse
+// @Failure      401      {object}  dto.MessageResponse
+// @Failure      403      {object}  dto.MessageResponse
+// @Failure      409      {object}  dto.MessageResponse
+// @Failure      500      {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /user/invites [post]
+func (h *InviteHandler) CreateInvite(ctx *gin.Context) {
+	userID, ok := contextUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.CreateInviteRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request body", "error": err.Error()})
+		return
+	}
+
+	invite, err := h.usecase.CreateInvite(ctx.Request.Context(), userID, &request)
+	if err != nil {
+		switch err.Error() {
+		case "expiry must be in the future":
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Expiry must be in the future", "error": err.Error()})
+		case "max uses not allowed":
+			ctx.JSON(http.StatusForbidden, gin.H{"message": "The requested number of uses is not allowed", "error": err.Error()})
+		case "expiry not allowed":
+			ctx.JSON(http.StatusForbidden, gin.H{"message": "The requested expiry is beyond the allowed invite lifetime", "error": err.Error()})
+		case "invite limit reached":
+			ctx.JSON(http.StatusConflict, gin.H{"message": "Too many active invite codes; revoke one first", "error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create invite code", "error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, invite)
+}
+
+// ListMyInvites godoc
+// @Summary      List my invite codes
+// @Description  Pages through the invite codes of the authenticated user, newest first, with the users who registered with each.
+// @Tags         invites
+// @Produce      json
+// @Param        page       query     int  false  "Page number (default 1)"
+// @Param        page_size  query     int  false  "Page size (1-100, default 20)"
+// @Success      200        {object}  dto.InviteListResponse
+// @Failure      400        {object}  dto.MessageResponse
+// @Failure      401        {object}  dto.MessageResponse
+// @Failure      500        {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /user/invites [get]
+func (h *InviteHandler) ListMyInvites(ctx *gin.Context) {
+	userID, ok := contextUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var query dto.InviteQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid query parameters", "error": err.Error()})
+		return
+	}
+
+	invites, err := h.usecase.ListMyInvites(ctx.Request.Context(), userID, &query)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to retrieve invite codes", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invites)
+}
+
+// RevokeInvite godoc
+// @Summary      Revoke an invite code
+// @Description  Revokes one of the authenticated user's invite codes. Accounts already registered with it are not affected.
+// @Tags         invites
+// @Produce      json
+// @Param        id   path      string  true  "Invite code ID"
+// @Success      200  {object}  dto.MessageResponse
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /user/invites/{id} [delete]
+func (h *InviteHandler) RevokeInvite(ctx *gin.Context) {
+	userID, ok := contextUserID(ctx)
+	if !ok {
+		return
+	}
+	inviteID, ok := uuidParam(ctx, "id", "invite code ID")
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.RevokeInvite(ctx.Request.Context(), userID, inviteID); err != nil {
+		writeInviteError(ctx, err, "Failed to revoke invite code")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Invite code revoked"})
+}
+
+// ListInvites godoc
+// @Summary      List all invite codes
+// @Description  Pages through every invite code, newest first, with the users who registered with each.
+// @Tags         admin
+// @Produce      json
+// @Param        created_by  query     string  false  "Only codes created by this user"
+// @Param        page        query     int     false  "Page number (default 1)"
+// @Param        page_size   query     int     false  "Page size (1-100, default 20)"
+// @Success      200         {object}  dto.InviteListResponse
+// @Failure      400         {object}  dto.MessageResponse
+// @Failure      401         {object}  dto.MessageResponse
+// @Failure      403         {object}  dto.MessageResponse
+// @Failure      500         {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /admin/invites [get]
+func (h *InviteHandler) ListInvites(ctx *gin.Context) {
+	var query dto.InviteQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid query parameters", "error": err.Error()})
+		return
+	}
+
+	invites, err := h.usecase.ListInvites(ctx.Request.Context(), &query)
+	if err != nil {
+		writeInviteError(ctx, err, "Failed to retrieve invite codes")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invites)
+}
+
+// RevokeAnyInvite godoc
+// @Summary      Revoke any invite code
+// @Description  Revokes an invite code regardless of who created it. The action is recorded in the audit log.
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "Invite code ID"
+// @Success      200  {object}  dto.MessageResponse
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      403  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /admin/invites/{id} [delete]
+func (h *InviteHandler) RevokeAnyInvite(ctx *gin.Context) {
+	actorID, ok := contextUserID(ctx)
+	if !ok {
+		return
+	}
+	inviteID, ok := uuidParam(ctx, "id", "invite code ID")
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.RevokeAnyInvite(ctx.Request.Context(), &dto.AdminActor{UserID: actorID, IP: ctx.ClientIP()}, inviteID); err != nil {
+		writeInviteError(ctx, err, "Failed to revoke invite code")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Invite code revoked"})
+}
+
+// writeInviteError maps invite usecase errors to responses, using message for
+// unexpected failures.
+func writeInviteError(ctx *gin.Context, err error, message string) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Invite code not found", "error": err.Error()})
+		return
+	}
+	if err.Error() == "invalid creator id" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": message, "error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"message": message, "error": err.Error()})
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"html/template"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+)
+
+// pageTemplate renders the pages opened from links in emails. Links only
+// show a page; the action behind them is a form POST, so mail scanners and
+// prefetchers that follow links do not trigger it.
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+{{- if .Action}}
+<form method="post" action="{{.Action}}">
+<input type="hidden" name="token" value="{{.Token}}">
+{{- if .AskPassword}}
+<p><label>New password <input type="password" name="new_password" minlength="8" autocomplete="new-password" required></label></p>
+{{- end}}
+<button type="submit">{{.Submit}}</button>
+</form>
+{{- end}}
+</body>
+</html>
+`))
+
+// page describes a page rendered by pageTemplate. Pages with an Action show
+// a form posting Token, and a new password when AskPassword is set, to it.
+type page struct {
+	Title       string
+	Message     string
+	Action      string
+	Token       string
+	Submit      string
+	AskPassword bool
+}
+
+// renderPage writes p as HTML. The URL of these pages carries a token, so
+// they are kept out of caches and referrers, and cannot be framed.
+func renderPage(ctx *gin.Context, status int, p page) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Referrer-Policy", "no-referrer")
+	ctx.Header("X-Frame-Options", "DENY")
+	ctx.Header("Content-Security-Policy", "default-src 'none'; form-action 'self'; frame-ancestors 'none'")
+	ctx.Render(status, render.HTML{Template: pageTemplate, Data: p})
+}
+
+// isFormPost reports whether the request was submitted by one of the HTML
+// forms, which should get a page back rather than JSON.
+func isFormPost(ctx *gin.Context) bool {
+	return ctx.ContentType() == binding.MIMEPOSTForm
+}
+
+// respond answers a form submission with a page titled title, and other
+// clients with the message and error as JSON. Pages never show err.
+func respond(ctx *gin.Context, status int, title string, message string, err error) {
+	if isFormPost(ctx) {
+		renderPage(ctx, status, page{Title: title, Message: message})
+		return
+	}
+	body := gin.H{"message": message}
+	if err != nil {
+		body["error"] = err.Error()
+	}
+	ctx.IndentedJSON(status, body)
+}
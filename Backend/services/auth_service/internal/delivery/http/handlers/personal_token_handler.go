@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"auth/internal/delivery/http/dto"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PersonalTokenHandler defines the HTTP handlers for personal access tokens.
+type PersonalTokenHandler struct {
+	usecase usecaseinterfaces.PersonalTokenUsecaseInterface
+}
+
+// NewPersonalTokenHandler creates a new instance of PersonalTokenHandler.
+func NewPersonalTokenHandler(usecase usecaseinterfaces.PersonalTokenUsecaseInterface) *PersonalTokenHandler {
+	return &PersonalTokenHandler{usecase: usecase}
+}
+
+// CreateToken godoc
+// @Summary      Create a personal access token
+// @Description  Issues a token for scripts and integrations, limited to the given scopes. The token is only returned in this response. Only administrators may request the admin scope.
+// @Tags         tokens
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.CreatePersonalTokenRequest  true  "Token name, scopes and optional expiry"
+// @Success      201      {object}  dto.CreatedPersonalTokenResponse
+// @Failure      400      {object}  dto.MessageResponse
+// @Failure      401      {object}  dto.MessageResponse
+// @Failure      403      {object}  dto.MessageResponse
+// @Failure      409      {object}  dto.MessageResponse
+// @Failure      500      {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /user/tokens [post]
+func (h *PersonalTokenHandler) CreateToken(ctx *gin.Context) {
+	userID, ok := contextUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.CreatePersonalTokenRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request body", "error": err.Error()})
+		return
+	}
+
+	token, err := h.usecase.CreateToken(ctx.Request.Context(), userID, &request)
+	if err != nil {
+		switch err.Error() {
+		case "expiry must be in the future":
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Expiry must be in the future", "error": err.Error()})
+		case "scope not allowed":
+			ctx.JSON(http.StatusForbidden, gin.H{"message": "Only administrators may create tokens with the admin scope", "error": err.Error()})
+		case "token limit reached":
+			ctx.JSON(http.StatusConflict, gin.H{"message": "Too many active tokens; revoke one first", "error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create token", "error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, token)
+}
+
+// ListTokens godoc
+// @Summary      List personal access tokens
+// @Description  Lists the active personal access tokens of the authenticated user. Only a short hint of each token is shown.
+// @Tags         tokens
+// @Produce      json
+// @Success      200  {array}   dto.PersonalTokenDTO
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /user/tokens [get]
+func (h *PersonalTokenHandler) ListTokens(ctx *gin.Context) {
+	userID, ok := contextUserID(ctx)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.usecase.ListTokens(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to retrieve tokens", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// RevokeToken godoc
+// @Summary      Revoke a personal access token
+// @Description  Revokes one of the authenticated user's personal access tokens. It stops working immediately.
+// @Tags         tokens
+// @Produce      json
+// @Param        id   path      string  true  "Token ID"
+// @Success      200  {object}  dto.MessageResponse
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /user/tokens/{id} [delete]
+func (h *PersonalTokenHandler) RevokeToken(ctx *gin.Context) {
+	userID, ok := contextUserID(ctx)
+	if !ok {
+		return
+	}
+
+	tokenID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid token ID", "error": err.Error()})
+		return
+	}
+
+	if err := h.usecase.RevokeToken(ctx.Request.Context(), userID, tokenID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Token not found", "error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to revoke token", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Token revoked"})
+}
+
+// contextUserID reads the user ID set by AuthMiddleware, answering with 500
+// when it is missing.
+func contextUserID(ctx *gin.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Get("user_id")
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "User ID not found in context"})
+		return uuid.Nil, false
+	}
+
+	parsedID, ok := userID.(uuid.UUID)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "User ID in context is not a valid UUID"})
+		return uuid.Nil, false
+	}
+	return parsedID, true
+}
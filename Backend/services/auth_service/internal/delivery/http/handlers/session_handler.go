@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"errors"
+	"io"
 	"net/http"
 
+	"auth/internal/delivery/http/cookie"
 	"auth/internal/delivery/http/dto"
 	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
 
@@ -15,11 +17,12 @@ import (
 // SessionHandler defines the HTTP handlers for sessions.
 type SessionHandler struct {
 	usecase usecaseinterfaces.SessionUsecaseInterface
+	cookies cookie.Config
 }
 
 // NewSessionHandler creates a new instance of SessionHandler.
-func NewSessionHandler(usecase usecaseinterfaces.SessionUsecaseInterface) *SessionHandler {
-	return &SessionHandler{usecase: usecase}
+func NewSessionHandler(usecase usecaseinterfaces.SessionUsecaseInterface, cookies cookie.Config) *SessionHandler {
+	return &SessionHandler{usecase: usecase, cookies: cookies}
 }
 
 // ListActiveSessions godoc
@@ -45,7 +48,7 @@ func (h *SessionHandler) ListActiveSessions(ctx *gin.Context) {
 		return
 	}
 
-	sessions, err := h.usecase.ListActiveSessions(parsedID)
+	sessions, err := h.usecase.ListActiveSessions(ctx.Request.Context(), parsedID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			ctx.JSON(http.StatusNotFound, gin.H{"message": "User not found", "error": err.Error()})
@@ -58,6 +61,57 @@ func (h *SessionHandler) ListActiveSessions(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, sessions)
 }
 
+// ListSessionHistory godoc
+// @Summary      Get the user's login history
+// @Description  Pages through all sessions of the authenticated user, including revoked and expired ones, newest first. Includes a summary of the distinct devices and IPs seen in the date range.
+// @Tags         sessions
+// @Produce      json
+// @Param        cursor  query     string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        limit   query     int     false  "Page size (1-100, default 20)"
+// @Param        from    query     string  false  "Only sessions created at or after this RFC 3339 time"
+// @Param        to      query     string  false  "Only sessions created before this RFC 3339 time"
+// @Success      200     {object}  dto.SessionHistoryResponse
+// @Failure      400     {object}  dto.MessageResponse
+// @Failure      401     {object}  dto.MessageResponse
+// @Failure      500     {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /sessions/history [get]
+func (h *SessionHandler) ListSessionHistory(ctx *gin.Context) {
+	userID, ok := ctx.Get("user_id")
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "User ID not found in context"})
+		return
+	}
+
+	parsedID, ok := userID.(uuid.UUID)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "User ID in context is not a valid UUID"})
+		return
+	}
+
+	var query dto.SessionHistoryQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid query parameters", "error": err.Error()})
+		return
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid query parameters", "error": "from must be before to"})
+		return
+	}
+
+	history, err := h.usecase.ListSessionHistory(ctx.Request.Context(), parsedID, &query)
+	if err != nil {
+		if err.Error() == "invalid cursor" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid cursor", "error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to retrieve login history", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
+
 // GetSession godoc
 // @Summary      Get a specific session
 // @Description  Retrieves a specific session by its ID for the authenticated user.
@@ -82,7 +136,7 @@ func (h *SessionHandler) GetSession(ctx *gin.Context) {
 		return
 	}
 
-	sessionDTO, err := h.usecase.GetSession(parsedID)
+	sessionDTO, err := h.usecase.GetSession(ctx.Request.Context(), parsedID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			ctx.JSON(http.StatusNotFound, gin.H{"message": "Session not found", "error": err.Error()})
@@ -119,7 +173,7 @@ func (h *SessionHandler) Logout(ctx *gin.Context) {
 		return
 	}
 
-	err := h.usecase.Logout(parsedID)
+	err := h.usecase.Logout(ctx.Request.Context(), parsedID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			ctx.JSON(http.StatusNotFound, gin.H{"message": "Session not found", "error": err.Error()})
@@ -129,6 +183,7 @@ func (h *SessionHandler) Logout(ctx *gin.Context) {
 		return
 	}
 
+	h.cookies.Clear(ctx)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Successfully logged out"})
 }
 
@@ -168,7 +223,7 @@ func (h *SessionHandler) LogoutAllExcept(ctx *gin.Context) {
 		return
 	}
 
-	err := h.usecase.LogoutAllExcept(parsedUserID, parsedSessionID)
+	err := h.usecase.LogoutAllExcept(ctx.Request.Context(), parsedUserID, parsedSessionID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			ctx.JSON(http.StatusNotFound, gin.H{"message": "User or session not found", "error": err.Error()})
@@ -181,9 +236,123 @@ func (h *SessionHandler) LogoutAllExcept(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Successfully logged out from other devices except this one"})
 }
 
+// RevokeSession godoc
+// @Summary      Revoke one of the user's sessions
+// @Description  Logs out a single session from the authenticated user's device list.
+// @Tags         sessions
+// @Produce      json
+// @Param        id   path      string  true  "Session ID"
+// @Success      200  {object}  dto.MessageResponse
+// @Failure      400  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.MessageResponse
+// @Failure      404  {object}  dto.MessageResponse
+// @Failure      500  {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /sessions/{id} [delete]
+func (h *SessionHandler) RevokeSession(ctx *gin.Context) {
+	userID, ok := ctx.Get("user_id")
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "User ID not found in context"})
+		return
+	}
+
+	parsedUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "User ID in context is not a valid UUID"})
+		return
+	}
+
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid session ID", "error": err.Error()})
+		return
+	}
+
+	err = h.usecase.RevokeUserSession(ctx.Request.Context(), parsedUserID, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Session not found", "error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to revoke session", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
+
+// SwitchCommunity godoc
+// @Summary      Switch the active community
+// @Description  Makes a community the user belongs to the one this session acts in, or clears it when community_id is null. Returns an access token that carries the community and the user's role there; later refreshes keep it. Browser clients in cookie mode get the token as a cookie instead.
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.SwitchCommunityRequest  true  "Community to act in"
+// @Success      200      {object}  dto.SwitchCommunityResponse
+// @Failure      400      {object}  dto.MessageResponse
+// @Failure      401      {object}  dto.MessageResponse
+// @Failure      403      {object}  dto.MessageResponse
+// @Failure      500      {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /sessions/community [post]
+func (h *SessionHandler) SwitchCommunity(ctx *gin.Context) {
+	userID, ok := ctx.Get("user_id")
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "User ID not found in context"})
+		return
+	}
+
+	parsedUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "User ID in context is not a valid UUID"})
+		return
+	}
+
+	sessionID, ok := ctx.Get("session_id")
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Session ID not found in context"})
+		return
+	}
+
+	parsedSessionID, ok := sessionID.(uuid.UUID)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Session ID in context is not a valid UUID"})
+		return
+	}
+
+	var req dto.SwitchCommunityRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request body", "error": err.Error()})
+		return
+	}
+
+	accessToken, err := h.usecase.SwitchCommunity(ctx.Request.Context(), parsedUserID, parsedSessionID, req.CommunityID)
+	if err != nil {
+		if err.Error() == "not a community member" {
+			ctx.JSON(http.StatusForbidden, gin.H{"message": "You are not a member of this community", "error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to switch community", "error": err.Error()})
+		return
+	}
+
+	// Requests authenticated by the access cookie get the new token the same way.
+	if h.cookies.Enabled && ctx.GetHeader("Authorization") == "" {
+		if _, err := h.cookies.SetTokens(ctx, accessToken, ""); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to switch community", "error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, dto.SwitchCommunityResponse{})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.SwitchCommunityResponse{AccessToken: accessToken})
+}
+
 // Refresh godoc
 // @Summary      Refresh access token
-// @Description  Refreshes an expired access token using a valid refresh token.
+// @Description  Refreshes an expired access token using a valid refresh token. When sliding session expiry is enabled the refresh token is rotated and the new one is returned.
+// @Description  Browser clients in cookie mode may omit the body: the refresh token cookie is used and the new tokens are set as cookies.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -191,18 +360,35 @@ func (h *SessionHandler) LogoutAllExcept(ctx *gin.Context) {
 // @Success      200      {object}  dto.RefreshResponse
 // @Failure      400      {object}  dto.MessageResponse
 // @Failure      401      {object}  dto.MessageResponse
+// @Failure      403      {object}  dto.AccountStatusResponse
 // @Failure      404      {object}  dto.MessageResponse
 // @Failure      500      {object}  dto.MessageResponse
 // @Router       /auth/refresh [post]
 func (h *SessionHandler) Refresh(ctx *gin.Context) {
 	var req dto.RefreshRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request body", "error": err.Error()})
 		return
 	}
 
-	accessToken, err := h.usecase.Refresh(req.RefreshToken)
+	fromCookie := false
+	if req.RefreshToken == "" && h.cookies.Enabled {
+		if token, err := ctx.Cookie(cookie.RefreshTokenName); err == nil {
+			req.RefreshToken, fromCookie = token, true
+		}
+	}
+	if req.RefreshToken == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Refresh token is required"})
+		return
+	}
+
+	accessToken, refreshToken, err := h.usecase.Refresh(ctx.Request.Context(), req.RefreshToken, clientInfo(ctx, req.DeviceName))
 	if err != nil {
+		var statusErr *usecaseinterfaces.AccountStatusError
+		if errors.As(err, &statusErr) {
+			ctx.JSON(http.StatusForbidden, statusErr.Response())
+			return
+		}
 		switch err.Error() {
 		case "session expired or revoked":
 			ctx.JSON(http.StatusUnauthorized, gin.H{"message": "Session expired or revoked", "error": err.Error()})
@@ -218,5 +404,14 @@ func (h *SessionHandler) Refresh(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, dto.RefreshResponse{AccessToken: accessToken})
+	if fromCookie {
+		if _, err := h.cookies.SetTokens(ctx, accessToken, refreshToken); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to refresh token", "error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"message": "Token refreshed"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.RefreshResponse{AccessToken: accessToken, RefreshToken: refreshToken})
 }
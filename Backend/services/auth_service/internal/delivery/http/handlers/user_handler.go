@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"auth/internal/delivery/http/cookie"
 	dto "auth/internal/delivery/http/dto"
 	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
 	"errors"
@@ -14,11 +15,12 @@ import (
 // UserHandler defines the HTTP handlers for user-related actions.
 type UserHandler struct {
 	userusecase usecaseinterfaces.UserUsecaseInterface
+	cookies     cookie.Config
 }
 
 // NewUserHandler creates a new instance of UserHandler.
-func NewUserHandler(userusecase usecaseinterfaces.UserUsecaseInterface) *UserHandler {
-	return &UserHandler{userusecase: userusecase}
+func NewUserHandler(userusecase usecaseinterfaces.UserUsecaseInterface, cookies cookie.Config) *UserHandler {
+	return &UserHandler{userusecase: userusecase, cookies: cookies}
 }
 
 // Register godoc
@@ -30,6 +32,7 @@ func NewUserHandler(userusecase usecaseinterfaces.UserUsecaseInterface) *UserHan
 // @Param        user  body      dto.RegisterUser  true  "User registration data"
 // @Success      201  {object}  dto.UserDto
 // @Failure      400  {object}  dto.MessageResponse
+// @Failure      403  {object}  dto.MessageResponse
 // @Failure      500  {object}  dto.MessageResponse
 // @Router       /auth/register [post]
 func (handler *UserHandler) Register(ctx *gin.Context) {
@@ -39,8 +42,16 @@ func (handler *UserHandler) Register(ctx *gin.Context) {
 		return
 	}
 
-	user, err := handler.userusecase.Register(&userdto)
+	user, err := handler.userusecase.Register(ctx.Request.Context(), &userdto)
 	if err != nil {
+		switch err.Error() {
+		case "registration is closed":
+			ctx.IndentedJSON(http.StatusForbidden, gin.H{"message": "Registration is closed", "error": err.Error()})
+			return
+		case "invite code required", "invalid invite code":
+			ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Cannot register user", "error": err.Error()})
+			return
+		}
 		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Cannot register user", "error": err.Error()})
 		return
 	}
@@ -48,9 +59,20 @@ func (handler *UserHandler) Register(ctx *gin.Context) {
 	ctx.IndentedJSON(http.StatusCreated, gin.H{"message": "Successfully registered", "data": user})
 }
 
+// RegistrationInfo godoc
+// @Summary      Get the registration mode
+// @Description  Reports whether registration is open, requires an invite code, or is closed.
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  dto.RegistrationInfoResponse
+// @Router       /auth/registration [get]
+func (handler *UserHandler) RegistrationInfo(ctx *gin.Context) {
+	ctx.IndentedJSON(http.StatusOK, dto.RegistrationInfoResponse{Mode: handler.userusecase.RegistrationMode()})
+}
+
 // Login godoc
 // @Summary      Login a user
-// @Description  Authenticates a user and returns access and refresh tokens.
+// @Description  Authenticates a user and returns access and refresh tokens. With use_cookies the tokens are set as HttpOnly cookies instead and a CSRF token is returned.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -58,7 +80,9 @@ func (handler *UserHandler) Register(ctx *gin.Context) {
 // @Success      200  {object}  dto.UserDto
 // @Failure      400  {object}  dto.MessageResponse
 // @Failure      401  {object}  dto.MessageResponse
+// @Failure      403  {object}  dto.MessageResponse
 // @Failure      404  {object}  dto.MessageResponse
+// @Failure      409  {object}  dto.MessageResponse
 // @Router       /auth/login [post]
 func (handler *UserHandler) Login(ctx *gin.Context) {
 	var request dto.LoginRequest
@@ -66,8 +90,12 @@ func (handler *UserHandler) Login(ctx *gin.Context) {
 		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Invalid request format", "error": err.Error()})
 		return
 	}
+	if request.UseCookies && !handler.cookies.Enabled {
+		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Cookie mode is not enabled"})
+		return
+	}
 
-	userdto, refreshtoken, accesstoken, err := handler.userusecase.Login(request.Identification, request.Password)
+	userdto, refreshtoken, accesstoken, err := handler.userusecase.Login(ctx.Request.Context(), request.Identification, request.Password, clientInfo(ctx, request.DeviceName))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) || err.Error() == "user not found" {
 			ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": "User not found", "error": err.Error()})
@@ -77,10 +105,37 @@ func (handler *UserHandler) Login(ctx *gin.Context) {
 			ctx.IndentedJSON(http.StatusUnauthorized, gin.H{"message": "Invalid credentials", "error": err.Error()})
 			return
 		}
+		if err.Error() == "password reset required" {
+			ctx.IndentedJSON(http.StatusForbidden, gin.H{"message": "A password reset is required before logging in", "error": err.Error()})
+			return
+		}
+		var statusErr *usecaseinterfaces.AccountStatusError
+		if errors.As(err, &statusErr) {
+			ctx.IndentedJSON(http.StatusForbidden, statusErr.Response())
+			return
+		}
+		if err.Error() == "session limit reached" {
+			ctx.IndentedJSON(http.StatusConflict, gin.H{"message": "Maximum number of active sessions reached, log out another device first", "error": err.Error()})
+			return
+		}
 		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Cannot login to the system", "error": err.Error()})
 		return
 	}
 
+	if request.UseCookies {
+		csrfToken, err := handler.cookies.StartSession(ctx, accesstoken, refreshtoken)
+		if err != nil {
+			ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Cannot login to the system", "error": err.Error()})
+			return
+		}
+		ctx.IndentedJSON(http.StatusOK, gin.H{
+			"message":    "Successfully logged in",
+			"user":       userdto,
+			"csrf_token": csrfToken,
+		})
+		return
+	}
+
 	ctx.IndentedJSON(http.StatusOK, gin.H{
 		"message":      "Successfully logged in",
 		"user":         userdto,
@@ -89,6 +144,227 @@ func (handler *UserHandler) Login(ctx *gin.Context) {
 	})
 }
 
+// ConfirmDenyLogin godoc
+// @Summary      Confirm a login that wasn't you
+// @Description  Target of the "this wasn't me" link in new-device emails. Only shows a page asking to confirm; the confirmation is posted back to the same URL.
+// @Tags         auth
+// @Produce      html
+// @Param        token  query     string  true  "Token from the new-device notification"
+// @Success      200    {string}  string  "Confirmation page"
+// @Failure      400    {string}  string  "Error page"
+// @Router       /auth/not-me [get]
+func (handler *UserHandler) ConfirmDenyLogin(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		renderPage(ctx, http.StatusBadRequest, page{Title: "Invalid link", Message: "This link is incomplete. Open it again from the email."})
+		return
+	}
+	renderPage(ctx, http.StatusOK, page{
+		Title:   "Wasn't you?",
+		Message: "Confirm to sign your account out of every device. We will then email you a link to choose a new password.",
+		Action:  ctx.Request.URL.Path,
+		Token:   token,
+		Submit:  "Sign out everywhere",
+	})
+}
+
+// DenyLogin godoc
+// @Summary      Report a login that wasn't you
+// @Description  Signs the account out of every device, requires a password reset and emails a link for performing it. Accepts the form posted by the confirmation page or JSON. Each link works once.
+// @Tags         auth
+// @Accept       json
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Produce      html
+// @Param        request  body      dto.DenyLoginRequest  true  "Token from the new-device notification"
+// @Success      200      {object}  dto.MessageResponse
+// @Failure      400      {object}  dto.MessageResponse
+// @Failure      404      {object}  dto.MessageResponse
+// @Failure      500      {object}  dto.MessageResponse
+// @Router       /auth/not-me [post]
+func (handler *UserHandler) DenyLogin(ctx *gin.Context) {
+	var request dto.DenyLoginRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		respond(ctx, http.StatusBadRequest, "Invalid link", "Token is required", err)
+		return
+	}
+
+	err := handler.userusecase.DenyLogin(ctx.Request.Context(), request.Token)
+	if err != nil {
+		if err.Error() == "invalid or expired link" {
+			respond(ctx, http.StatusBadRequest, "Invalid link", "This link is invalid, expired or was already used.", err)
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respond(ctx, http.StatusNotFound, "Invalid link", "User not found", err)
+			return
+		}
+		respond(ctx, http.StatusInternalServerError, "Something went wrong", "Cannot secure the account, please try again.", err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, "Account secured", "All sessions have been signed out. Check your email for a link to choose a new password.", nil)
+}
+
+// ResetPasswordForm godoc
+// @Summary      Show the password reset form
+// @Description  Target of the link in password reset emails. Shows a form that posts the new password with the token.
+// @Tags         auth
+// @Produce      html
+// @Param        token  query     string  true  "Token from the password reset email"
+// @Success      200    {string}  string  "Reset form"
+// @Failure      400    {string}  string  "Error page"
+// @Router       /auth/password/reset [get]
+func (handler *UserHandler) ResetPasswordForm(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		renderPage(ctx, http.StatusBadRequest, page{Title: "Invalid link", Message: "This link is incomplete. Open it again from the email."})
+		return
+	}
+	renderPage(ctx, http.StatusOK, page{
+		Title:       "Choose a new password",
+		Message:     "Your new password must be at least 8 characters long.",
+		Action:      ctx.Request.URL.Path,
+		Token:       token,
+		Submit:      "Reset password",
+		AskPassword: true,
+	})
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Sets a new password using a reset token and signs the account out of every device. Accepts the reset form or JSON.
+// @Tags         auth
+// @Accept       json
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Produce      html
+// @Param        request  body      dto.ResetPasswordRequest  true  "Reset token and new password"
+// @Success      200      {object}  dto.MessageResponse
+// @Failure      400      {object}  dto.MessageResponse
+// @Failure      404      {object}  dto.MessageResponse
+// @Failure      500      {object}  dto.MessageResponse
+// @Router       /auth/password/reset [post]
+func (handler *UserHandler) ResetPassword(ctx *gin.Context) {
+	var request dto.ResetPasswordRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		respond(ctx, http.StatusBadRequest, "Invalid password", "Invalid request format, the new password needs at least 8 characters", err)
+		return
+	}
+
+	err := handler.userusecase.ResetPassword(ctx.Request.Context(), request.Token, request.NewPassword)
+	if err != nil {
+		if err.Error() == "invalid or expired link" {
+			respond(ctx, http.StatusBadRequest, "Invalid link", "Invalid or expired reset token", err)
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respond(ctx, http.StatusNotFound, "Invalid link", "User not found", err)
+			return
+		}
+		respond(ctx, http.StatusInternalServerError, "Something went wrong", "Cannot reset password", err)
+		return
+	}
+
+	respond(ctx, http.StatusOK, "Password changed", "Password has been reset, please log in again", nil)
+}
+
+// CancelDeletion godoc
+// @Summary      Cancel a pending account deletion
+// @Description  Reactivates an account scheduled for deletion during its grace period. Log in again afterwards.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials  body      dto.CancelDeletionRequest  true  "Account credentials"
+// @Success      200          {object}  dto.MessageResponse
+// @Failure      400          {object}  dto.MessageResponse
+// @Failure      401          {object}  dto.MessageResponse
+// @Failure      404          {object}  dto.MessageResponse
+// @Failure      409          {object}  dto.MessageResponse
+// @Failure      410          {object}  dto.MessageResponse
+// @Failure      500          {object}  dto.MessageResponse
+// @Router       /auth/account/cancel-deletion [post]
+func (handler *UserHandler) CancelDeletion(ctx *gin.Context) {
+	var request dto.CancelDeletionRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Invalid request format", "error": err.Error()})
+		return
+	}
+
+	err := handler.userusecase.CancelDeletion(ctx.Request.Context(), request.Identification, request.Password, clientInfo(ctx, ""))
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": "User not found", "error": err.Error()})
+		case "invalid credentials":
+			ctx.IndentedJSON(http.StatusUnauthorized, gin.H{"message": "Invalid credentials", "error": err.Error()})
+		case "account is not pending deletion":
+			ctx.IndentedJSON(http.StatusConflict, gin.H{"message": "The account is not scheduled for deletion", "error": err.Error()})
+		case "deletion grace period has ended":
+			ctx.IndentedJSON(http.StatusGone, gin.H{"message": "The grace period has ended and the account is being deleted", "error": err.Error()})
+		default:
+			ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Cannot cancel account deletion", "error": err.Error()})
+		}
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"message": "Account deletion cancelled, please log in again"})
+}
+
+// DeleteMe godoc
+// @Summary      Delete the authenticated user's account
+// @Description  Schedules the account for deletion after a grace period and signs it out everywhere. The password must be entered again. The deletion can be cancelled until the returned time.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.DeleteAccountRequest  true  "Current password"
+// @Success      202      {object}  dto.DeleteAccountResponse
+// @Failure      400      {object}  dto.MessageResponse
+// @Failure      401      {object}  dto.MessageResponse
+// @Failure      404      {object}  dto.MessageResponse
+// @Failure      500      {object}  dto.MessageResponse
+// @Security     Bearer
+// @Router       /user/me [delete]
+func (handler *UserHandler) DeleteMe(ctx *gin.Context) {
+	userId, ok := ctx.Get("user_id")
+	if !ok {
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "User ID not found in context"})
+		return
+	}
+
+	parsedId, ok := userId.(uuid.UUID)
+	if !ok {
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "User ID in context is not a valid UUID"})
+		return
+	}
+
+	var request dto.DeleteAccountRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Invalid request format", "error": err.Error()})
+		return
+	}
+
+	deleteAt, err := handler.userusecase.RequestDeletion(ctx.Request.Context(), parsedId, request.Password, clientInfo(ctx, ""))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": "User not found", "error": err.Error()})
+			return
+		}
+		if err.Error() == "invalid credentials" {
+			ctx.IndentedJSON(http.StatusUnauthorized, gin.H{"message": "Invalid password", "error": err.Error()})
+			return
+		}
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Cannot delete account", "error": err.Error()})
+		return
+	}
+
+	handler.cookies.Clear(ctx)
+	ctx.IndentedJSON(http.StatusAccepted, dto.DeleteAccountResponse{
+		Message:  "Your account is scheduled for deletion and has been signed out everywhere",
+		DeleteAt: deleteAt,
+	})
+}
+
 // GetMe godoc
 // @Summary      Get authenticated user's profile
 // @Description  Retrieves the profile of the user authenticated by the JWT token.
@@ -111,7 +387,7 @@ func (handler *UserHandler) GetMe(ctx *gin.Context) {
 		return
 	}
 
-	userdto, err := handler.userusecase.GetUserProfile(parsedId)
+	userdto, err := handler.userusecase.GetUserProfile(ctx.Request.Context(), parsedId)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": "User not found", "error": err.Error()})
@@ -147,7 +423,7 @@ func (handler *UserHandler) IsVerified(ctx *gin.Context) {
 		return
 	}
 
-	isverified, err := handler.userusecase.IsVerifiedUser(parsedId)
+	isverified, err := handler.userusecase.IsVerifiedUser(ctx.Request.Context(), parsedId)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": "User not found", "error": err.Error()})
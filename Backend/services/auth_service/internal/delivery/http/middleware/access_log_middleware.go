@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"auth/internal/infrastructure/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// quietPaths are polled by probes and scrapers; their successful requests
+// are only logged at debug level.
+var quietPaths = map[string]bool{"/healthz": true, "/readyz": true, "/metrics": true}
+
+// AccessLogMiddleware logs one record per request once it has been served,
+// with the status, latency and the user, session or access token set by
+// AuthMiddleware. Server errors are logged as errors and client errors as
+// warnings. Sensitive query parameters, such as signed link tokens, are
+// redacted.
+func AccessLogMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.String("route", ctx.FullPath()),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", ctx.Writer.Size()),
+			slog.String("client_ip", ctx.ClientIP()),
+			slog.String("user_agent", ctx.Request.UserAgent()),
+		}
+		if query := ctx.Request.URL.RawQuery; query != "" {
+			attrs = append(attrs, slog.String("query", logging.RedactQuery(query)))
+		}
+		for _, key := range []string{"user_id", "session_id", "token_id"} {
+			if value, ok := ctx.Get(key); ok {
+				attrs = append(attrs, slog.Any(key, value))
+			}
+		}
+		if len(ctx.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", ctx.Errors.String()))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		case quietPaths[ctx.Request.URL.Path]:
+			level = slog.LevelDebug
+		}
+		slog.LogAttrs(ctx.Request.Context(), level, "Request served", attrs...)
+	}
+}
+
+// RecoveryMiddleware turns a panic in a handler into a 500 response and logs
+// it, with the request ID, instead of dropping the connection.
+func RecoveryMiddleware() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(ctx *gin.Context, recovered any) {
+		slog.ErrorContext(ctx.Request.Context(), "Panic while serving request",
+			"method", ctx.Request.Method,
+			"path", ctx.Request.URL.Path,
+			"panic", recovered,
+			"stack", string(debug.Stack()),
+		)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "Internal server error"})
+	})
+}
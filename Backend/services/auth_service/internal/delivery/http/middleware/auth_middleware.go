@@ -1,36 +1,71 @@
 package middleware
 
 import (
+	"auth/internal/delivery/http/cookie"
 	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+	"auth/internal/domain/entity"
 	"auth/internal/services"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-func AuthMiddleware(tokenSerivce services.TokenService, sessionUsecase usecaseinterfaces.SessionUsecaseInterface) gin.HandlerFunc{
+// AuthMiddleware accepts both session access tokens and personal access
+// tokens. Session tokens are granted every scope; personal access tokens only
+// the ones they were created with. The access token cookie is only read when
+// cookie mode is enabled.
+func AuthMiddleware(tokenSerivce services.TokenService, sessionUsecase usecaseinterfaces.SessionUsecaseInterface, personalTokenUsecase usecaseinterfaces.PersonalTokenUsecaseInterface, cookies cookie.Config) gin.HandlerFunc{
 	return func(ctx *gin.Context) {
+		var accessToken string
 		authHeader := ctx.GetHeader("Authorization")
-		if authHeader == ""{
+		if authHeader != "" {
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || parts[0] != "Bearer"{
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Invalid Authorization header format"})
+				return
+			}
+			accessToken = parts[1]
+		} else if tokenCookie, err := ctx.Cookie(cookie.AccessTokenName); cookies.Enabled && err == nil && tokenCookie != "" {
+			// Browser clients in cookie mode; CSRFMiddleware guards their unsafe requests.
+			accessToken = tokenCookie
+		} else {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Authorization header is required"})
 			return
 		}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer"{
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Invalid Authorization header format"})
+		if strings.HasPrefix(accessToken, entity.PersonalAccessTokenPrefix) {
+			principal, err := personalTokenUsecase.Authenticate(ctx.Request.Context(), accessToken)
+			if err != nil {
+				abortAccountError(ctx, err)
+				return
+			}
+			ctx.Set("user_id", principal.UserID)
+			ctx.Set("token_id", principal.TokenID)
+			ctx.Set("roles", principal.Roles)
+			ctx.Set("scopes", principal.Scopes)
+			ctx.Set("auth_method", AuthMethodToken)
+			ctx.Next()
 			return
 		}
 
-		accessToken := parts[1]
 		claims, err := tokenSerivce.ParseAccessToken(accessToken)
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Invalid or expired token", "error": err.Error()})
 			return
 		}
 
-		active, err := sessionUsecase.IsSessionActive(claims.SessionID)
+		// Checked before the session so that a blocked account gets its status
+		// code rather than a generic "revoked".
+		if err := sessionUsecase.CheckAccount(ctx.Request.Context(), claims.UserID); err != nil {
+			abortAccountError(ctx, err)
+			return
+		}
+
+		// Also records the session's activity for the idle timeout.
+		active, err := sessionUsecase.IsSessionActive(ctx.Request.Context(), claims.SessionID)
 		if err != nil || !active {
     		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Session expired or revoked"})
     		return
@@ -38,7 +73,35 @@ func AuthMiddleware(tokenSerivce services.TokenService, sessionUsecase usecasein
 
 		ctx.Set("user_id", claims.UserID)
 		ctx.Set("session_id", claims.SessionID)
+		ctx.Set("roles", claims.Roles)
+		if claims.Community != nil {
+			ctx.Set("community_id", claims.Community.ID)
+			ctx.Set("community_role", claims.Community.Role)
+		}
+		ctx.Set("scopes", entity.AllScopes)
+		ctx.Set("auth_method", AuthMethodSession)
 
 		ctx.Next()
 	}
-}
\ No newline at end of file
+}
+
+func abortAccountError(ctx *gin.Context, err error) {
+	var statusErr *usecaseinterfaces.AccountStatusError
+	if errors.As(err, &statusErr) {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, statusErr.Response())
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Account not found"})
+		return
+	}
+	if err.Error() == "password reset required" {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "A password reset is required before using this account", "error": err.Error()})
+		return
+	}
+	if err.Error() == "invalid token" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Invalid, expired or revoked token"})
+		return
+	}
+	ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "Failed to verify account", "error": err.Error()})
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"auth/internal/infrastructure/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware records the latency of every request by route template,
+// so that paths with IDs share one series. Requests matching no route are
+// grouped under "unmatched".
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		metrics.HTTPRequestsInFlight.Inc()
+		defer metrics.HTTPRequestsInFlight.Dec()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := ctx.Writer.Status()
+		metrics.HTTPRequestDuration.WithLabelValues(ctx.Request.Method, route, strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+		if status == http.StatusTooManyRequests {
+			metrics.RateLimitRejections.WithLabelValues(route).Inc()
+		}
+	}
+}
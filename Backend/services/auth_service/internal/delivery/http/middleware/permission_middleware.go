@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"auth/internal/delivery/http/dto"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequirePermission only lets requests through whose caller holds permission.
+// It must run after AuthMiddleware and evaluates the global roles from the
+// access token claims. When communityParam is set, the check is scoped to the
+// community whose ID is in that path parameter, with the caller's current
+// role there.
+func RequirePermission(authz usecaseinterfaces.AuthzUsecaseInterface, permission string, communityParam string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, ok := ctx.Get("user_id")
+		parsedUserID, valid := userID.(uuid.UUID)
+		if !ok || !valid {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "User ID not found in context"})
+			return
+		}
+		principal := &dto.AuthzPrincipal{UserID: parsedUserID, Roles: ctx.GetStringSlice("roles")}
+
+		var communityID *uuid.UUID
+		if communityParam != "" {
+			parsed, err := uuid.Parse(ctx.Param(communityParam))
+			if err != nil {
+				ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"message": "Invalid community ID", "error": err.Error()})
+				return
+			}
+			communityID = &parsed
+		}
+
+		decision, err := authz.Authorize(ctx.Request.Context(), principal, permission, communityID)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "Failed to check permissions", "error": err.Error()})
+			return
+		}
+		if !decision.Allowed {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "Insufficient permissions", "error": decision.Reason})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// ServiceTokenHeader carries the shared secret of internal service calls.
+const ServiceTokenHeader = "X-Service-Token"
+
+// RequireServiceToken guards endpoints meant for other services, which must
+// send token in ServiceTokenHeader. With an empty token the endpoints are
+// switched off.
+func RequireServiceToken(token string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if token == "" {
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"message": "Service endpoints are not configured"})
+			return
+		}
+		provided := ctx.GetHeader(ServiceTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Invalid service token"})
+			return
+		}
+		ctx.Next()
+	}
+}
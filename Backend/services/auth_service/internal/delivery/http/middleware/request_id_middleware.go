@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"regexp"
+
+	"auth/internal/infrastructure/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern limits accepted request IDs to short, log-safe values.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestIDMiddleware keeps the X-Request-ID sent by the client or a proxy,
+// or generates one, and echoes it in the response. The ID is stored as
+// "request_id" in the gin context and carried by the request context, so logs
+// written while serving the request include it.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = uuid.NewString()
+		}
+		ctx.Set("request_id", id)
+		ctx.Header(RequestIDHeader, id)
+		ctx.Request = ctx.Request.WithContext(logging.WithRequestID(ctx.Request.Context(), id))
+
+		ctx.Next()
+	}
+}
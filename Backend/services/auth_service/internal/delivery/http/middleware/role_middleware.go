@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole only lets requests through whose access token carries one of
+// roles. It must run after AuthMiddleware, which puts the roles in the context.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		granted, _ := ctx.Get("roles")
+		tokenRoles, _ := granted.([]string)
+		for _, role := range tokenRoles {
+			if slices.Contains(roles, role) {
+				ctx.Next()
+				return
+			}
+		}
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "Insufficient permissions"})
+	}
+}
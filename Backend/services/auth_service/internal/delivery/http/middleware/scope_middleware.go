@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Values of the "auth_method" context key set by AuthMiddleware.
+const (
+	AuthMethodSession = "session"
+	AuthMethodToken   = "token"
+)
+
+// RequireScope only lets requests through that were granted all of scopes.
+// Session logins hold every scope, so in practice it restricts personal
+// access tokens. It must run after AuthMiddleware.
+func RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		granted, _ := ctx.Get("scopes")
+		tokenScopes, _ := granted.([]string)
+		for _, scope := range scopes {
+			if !slices.Contains(tokenScopes, scope) {
+				ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "Token is missing the required scope", "error": "missing scope " + scope})
+				return
+			}
+		}
+		ctx.Next()
+	}
+}
+
+// RequireSession rejects requests authenticated with a personal access token,
+// for endpoints that act on the current session or manage tokens themselves.
+func RequireSession() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetString("auth_method") != AuthMethodSession {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "This endpoint requires a session login"})
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -1,55 +1,188 @@
 package http
 
 import (
+	"log/slog"
+	nethttp "net/http"
+	"os"
+
+	"auth/internal/delivery/http/cookie"
 	"auth/internal/delivery/http/handlers"
 	"auth/internal/delivery/http/middleware"
 	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+	"auth/internal/domain/entity"
+	"auth/internal/infrastructure/tracing"
 	"auth/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // RouterConfig holds all the handlers required by the router.
 type RouterConfig struct {
     UserHandler    *handlers.UserHandler
     SessionHandler *handlers.SessionHandler
+    AdminHandler   *handlers.AdminHandler
+    DataExportHandler *handlers.DataExportHandler
+    PersonalTokenHandler *handlers.PersonalTokenHandler
+    CommunityHandler *handlers.CommunityHandler
+    AuthzHandler *handlers.AuthzHandler
+    InviteHandler *handlers.InviteHandler
+    HealthHandler *handlers.HealthHandler
     TokenService services.TokenService
     SessionUsecase usecaseinterfaces.SessionUsecaseInterface
+    PersonalTokenUsecase usecaseinterfaces.PersonalTokenUsecaseInterface
+    AuthzUsecase usecaseinterfaces.AuthzUsecaseInterface
+    // Cookies decides whether the access token cookie is accepted.
+    Cookies cookie.Config
+    // ServiceToken authenticates other services calling the authz API.
+    ServiceToken string
+    // TrustedProxies lists the proxy IPs/CIDRs whose forwarding headers are
+    // used to resolve the client IP. When empty, the remote address is used.
+    TrustedProxies []string
 }
 
 // SetupRouter configures and returns the Gin router.
 func SetupRouter(config *RouterConfig) *gin.Engine {
     router := gin.New()
+    if err := router.SetTrustedProxies(config.TrustedProxies); err != nil {
+        slog.Error("Invalid trusted proxy configuration", "error", err)
+        os.Exit(1)
+    }
+    // The request ID and the server span come first so that every later log
+    // carries them. The span continues the trace of an incoming traceparent.
+    router.Use(
+        middleware.RequestIDMiddleware(),
+        otelgin.Middleware(tracing.ServiceName, otelgin.WithFilter(tracedRequest)),
+        middleware.AccessLogMiddleware(),
+        middleware.MetricsMiddleware(),
+        middleware.RecoveryMiddleware(),
+    )
+
+    router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+    router.GET("/healthz", config.HealthHandler.Liveness)
+    router.GET("/readyz", config.HealthHandler.Readiness)
+
+    authenticated := middleware.AuthMiddleware(config.TokenService, config.SessionUsecase, config.PersonalTokenUsecase, config.Cookies)
+
+    // Links sent by email carry their authorisation in a token rather than in
+    // cookies, so they need no CSRF check. The pages post back to themselves.
+    links := router.Group("/api/v1/auth")
+    {
+        links.GET("/not-me", config.UserHandler.ConfirmDenyLogin)
+        links.POST("/not-me", config.UserHandler.DenyLogin)
+        links.GET("/password/reset", config.UserHandler.ResetPasswordForm)
+        links.POST("/password/reset", config.UserHandler.ResetPassword)
+    }
 
     // Define API routes
     api := router.Group("/api/v1")
+    api.Use(middleware.CSRFMiddleware())
     {
         // Public routes
         public := api.Group("/auth")
         {
             public.POST("/register", config.UserHandler.Register)
+            public.GET("/registration", config.UserHandler.RegistrationInfo)
             public.POST("/login", config.UserHandler.Login)
             public.POST("/refresh", config.SessionHandler.Refresh)
+            public.POST("/account/cancel-deletion", config.UserHandler.CancelDeletion)
         }
 
+        // Called by other services rather than users
+        api.POST("/authz/check", middleware.RequireServiceToken(config.ServiceToken), config.AuthzHandler.Check)
+
+        // Signed links sent by email carry their own authorisation
+        api.GET("/exports/download", config.DataExportHandler.Download)
+
         // Protected routes (will need an auth middleware)
         protected := api.Group("/user")
-        protected.Use(middleware.AuthMiddleware(config.TokenService,config.SessionUsecase))
+        protected.Use(authenticated)
         {
-            protected.GET("/me", config.UserHandler.GetMe)
-            protected.GET("/is-verified", config.UserHandler.IsVerified)
+            protected.GET("/me", middleware.RequireScope(entity.ScopeUserRead), config.UserHandler.GetMe)
+            protected.DELETE("/me", middleware.RequireScope(entity.ScopeUserWrite), config.UserHandler.DeleteMe)
+            protected.POST("/me/export", middleware.RequireScope(entity.ScopeUserWrite), config.DataExportHandler.RequestExport)
+            protected.GET("/me/export/:id", middleware.RequireScope(entity.ScopeUserRead), config.DataExportHandler.GetExport)
+            protected.GET("/is-verified", middleware.RequireScope(entity.ScopeUserRead), config.UserHandler.IsVerified)
+            protected.GET("/communities", middleware.RequireScope(entity.ScopeCommunitiesRead), config.CommunityHandler.ListMyCommunities)
+            protected.GET("/invitations", middleware.RequireScope(entity.ScopeCommunitiesRead), config.CommunityHandler.ListMyInvitations)
+            protected.POST("/invitations/:id/accept", middleware.RequireScope(entity.ScopeCommunitiesWrite), config.CommunityHandler.AcceptInvitation)
+            protected.POST("/invitations/:id/decline", middleware.RequireScope(entity.ScopeCommunitiesWrite), config.CommunityHandler.DeclineInvitation)
+            protected.POST("/invites", middleware.RequireScope(entity.ScopeUserWrite), config.InviteHandler.CreateInvite)
+            protected.GET("/invites", middleware.RequireScope(entity.ScopeUserRead), config.InviteHandler.ListMyInvites)
+            protected.DELETE("/invites/:id", middleware.RequireScope(entity.ScopeUserWrite), config.InviteHandler.RevokeInvite)
+
+            // Tokens cannot be used to mint or manage other tokens
+            tokenRoutes := protected.Group("/tokens", middleware.RequireSession())
+            tokenRoutes.POST("", config.PersonalTokenHandler.CreateToken)
+            tokenRoutes.GET("", config.PersonalTokenHandler.ListTokens)
+            tokenRoutes.DELETE("/:id", config.PersonalTokenHandler.RevokeToken)
         }
 
         // More protected routes
         sessionRoutes := api.Group("/sessions")
-        sessionRoutes.Use(middleware.AuthMiddleware(config.TokenService, config.SessionUsecase))
+        sessionRoutes.Use(authenticated)
+        {
+            sessionRoutes.GET("/me", middleware.RequireScope(entity.ScopeSessionsRead), config.SessionHandler.ListActiveSessions)
+            sessionRoutes.GET("/history", middleware.RequireScope(entity.ScopeSessionsRead), config.SessionHandler.ListSessionHistory)
+            sessionRoutes.DELETE("/:id", middleware.RequireScope(entity.ScopeSessionsWrite), config.SessionHandler.RevokeSession)
+
+            // These act on the session the request was made with
+            sessionRoutes.GET("/get-session", middleware.RequireSession(), config.SessionHandler.GetSession)
+            sessionRoutes.DELETE("/logout", middleware.RequireSession(), config.SessionHandler.Logout)
+            sessionRoutes.DELETE("/all-except", middleware.RequireSession(), config.SessionHandler.LogoutAllExcept)
+            sessionRoutes.POST("/community", middleware.RequireSession(), config.SessionHandler.SwitchCommunity)
+        }
+
+        communityRoutes := api.Group("/communities")
+        communityRoutes.Use(authenticated)
         {
-            sessionRoutes.GET("/me", config.SessionHandler.ListActiveSessions)
-            sessionRoutes.GET("/get-session", config.SessionHandler.GetSession)
-            sessionRoutes.DELETE("/logout", config.SessionHandler.Logout)
-            sessionRoutes.DELETE("/all-except", config.SessionHandler.LogoutAllExcept)
+            read := middleware.RequireScope(entity.ScopeCommunitiesRead)
+            write := middleware.RequireScope(entity.ScopeCommunitiesWrite)
+
+            communityRoutes.POST("", write, middleware.RequirePermission(config.AuthzUsecase, entity.PermissionCommunitiesCreate, ""), config.CommunityHandler.CreateCommunity)
+            communityRoutes.GET("/:id", read, config.CommunityHandler.GetCommunity)
+            communityRoutes.DELETE("/:id", write, config.CommunityHandler.DeleteCommunity)
+            communityRoutes.GET("/:id/members", read, config.CommunityHandler.ListMembers)
+            communityRoutes.PUT("/:id/members/:userId/role", write, config.CommunityHandler.ChangeMemberRole)
+            communityRoutes.DELETE("/:id/members/:userId", write, config.CommunityHandler.RemoveMember)
+            communityRoutes.POST("/:id/leave", write, config.CommunityHandler.Leave)
+            communityRoutes.POST("/:id/invitations", write, config.CommunityHandler.Invite)
+            communityRoutes.GET("/:id/invitations", read, config.CommunityHandler.ListInvitations)
+            communityRoutes.DELETE("/:id/invitations/:invitationId", write, config.CommunityHandler.RevokeInvitation)
+            communityRoutes.POST("/:id/join-requests", write, config.CommunityHandler.RequestToJoin)
+            communityRoutes.GET("/:id/join-requests", read, config.CommunityHandler.ListJoinRequests)
+            communityRoutes.POST("/:id/join-requests/:requestId/approve", write, config.CommunityHandler.ApproveJoinRequest)
+            communityRoutes.POST("/:id/join-requests/:requestId/reject", write, config.CommunityHandler.RejectJoinRequest)
+        }
+
+        // Admin routes
+        adminRoutes := api.Group("/admin")
+        adminRoutes.Use(authenticated, middleware.RequireRole(entity.RoleAdmin), middleware.RequireScope(entity.ScopeAdmin))
+        {
+            adminRoutes.GET("/users", config.AdminHandler.ListUsers)
+            adminRoutes.GET("/users/:id", config.AdminHandler.GetUser)
+            adminRoutes.GET("/users/:id/sessions", config.AdminHandler.ListUserSessions)
+            adminRoutes.POST("/users/:id/suspend", config.AdminHandler.SuspendUser)
+            adminRoutes.POST("/users/:id/unsuspend", config.AdminHandler.UnsuspendUser)
+            adminRoutes.PUT("/users/:id/status", config.AdminHandler.ChangeStatus)
+            adminRoutes.POST("/users/:id/verify", config.AdminHandler.VerifyUser)
+            adminRoutes.PUT("/users/:id/role", config.AdminHandler.ChangeRole)
+            adminRoutes.POST("/users/:id/logout", config.AdminHandler.ForceLogout)
+            adminRoutes.GET("/audit-logs", config.AdminHandler.ListAuditLogs)
+            adminRoutes.GET("/invites", config.InviteHandler.ListInvites)
+            adminRoutes.DELETE("/invites/:id", config.InviteHandler.RevokeAnyInvite)
         }
     }
 
     return router
-}
\ No newline at end of file
+}
+
+// tracedRequest leaves probe and scrape requests out of traces.
+func tracedRequest(request *nethttp.Request) bool {
+    switch request.URL.Path {
+    case "/healthz", "/readyz", "/metrics":
+        return false
+    }
+    return true
+}
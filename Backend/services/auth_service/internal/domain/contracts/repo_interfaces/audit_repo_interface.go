@@ -0,0 +1,28 @@
+package repointerfaces
+
+import (
+	"context"
+
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// AuditFilter selects audit log entries. Nil or empty fields match everything.
+type AuditFilter struct {
+	ActorID      *uuid.UUID
+	TargetUserID *uuid.UUID
+	// SubjectID matches entries where the user is either the actor or the target.
+	SubjectID *uuid.UUID
+	Action       string
+	Offset       int
+	Limit        int
+}
+
+type AuditRepoInterface interface {
+	// WithContext returns a copy of the repository whose queries run with
+	// ctx, so they are cancelled with the request and traced under its span.
+	WithContext(ctx context.Context) AuditRepoInterface
+	Create(entry *entity.AuditLog) error
+	List(filter AuditFilter) ([]*entity.AuditLog, int64, error)
+}
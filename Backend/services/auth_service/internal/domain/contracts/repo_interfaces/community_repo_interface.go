@@ -0,0 +1,74 @@
+package repointerfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// ErrLastOwner is returned by CommunityRepoInterface when a change would leave
+// the community without an owner.
+var ErrLastOwner = errors.New("community must keep an owner")
+
+type CommunityRepoInterface interface {
+	// WithContext returns a copy of the repository whose queries run with
+	// ctx, so they are cancelled with the request and traced under its span.
+	WithContext(ctx context.Context) CommunityRepoInterface
+	// Create stores the community and makes ownerId its first owner.
+	Create(community *entity.Community, ownerId uuid.UUID) error
+	GetById(Id uuid.UUID) (*entity.Community, error)
+	GetBySlug(slug string) (*entity.Community, error)
+	Delete(Id uuid.UUID) error
+	// ListForUser returns the user's memberships with their communities loaded.
+	ListForUser(userId uuid.UUID) ([]*entity.CommunityMembership, error)
+	GetMembership(communityId uuid.UUID, userId uuid.UUID) (*entity.CommunityMembership, error)
+	// ListMembers returns a page of memberships with their users loaded, and
+	// the total number of members.
+	ListMembers(communityId uuid.UUID, offset int, limit int) ([]*entity.CommunityMembership, int64, error)
+	// UpdateMemberRole and RemoveMember return ErrLastOwner rather than take
+	// away the community's last owner. The check and the change run in one
+	// transaction holding the owners' rows locked, so concurrent changes
+	// cannot both pass it.
+	UpdateMemberRole(communityId uuid.UUID, userId uuid.UUID, role string) error
+	RemoveMember(communityId uuid.UUID, userId uuid.UUID) error
+}
+
+type CommunityInvitationRepoInterface interface {
+	// WithContext returns a copy of the repository whose queries run with
+	// ctx, so they are cancelled with the request and traced under its span.
+	WithContext(ctx context.Context) CommunityInvitationRepoInterface
+	Create(invitation *entity.CommunityInvitation) error
+	GetById(Id uuid.UUID) (*entity.CommunityInvitation, error)
+	// FindPending returns the user's open invitation to the community, or
+	// gorm.ErrRecordNotFound.
+	FindPending(communityId uuid.UUID, inviteeId uuid.UUID, now time.Time) (*entity.CommunityInvitation, error)
+	// ListPendingForCommunity loads each invitee; ListPendingForUser loads
+	// each community.
+	ListPendingForCommunity(communityId uuid.UUID, now time.Time) ([]*entity.CommunityInvitation, error)
+	ListPendingForUser(userId uuid.UUID, now time.Time) ([]*entity.CommunityInvitation, error)
+	// Accept marks a pending invitation accepted and adds the membership in
+	// one transaction. It reports false when the invitation was no longer pending.
+	Accept(Id uuid.UUID, membership *entity.CommunityMembership) (bool, error)
+	// Close moves a pending invitation to status and reports whether it was pending.
+	Close(Id uuid.UUID, status string) (bool, error)
+}
+
+type CommunityJoinRequestRepoInterface interface {
+	// WithContext returns a copy of the repository whose queries run with
+	// ctx, so they are cancelled with the request and traced under its span.
+	WithContext(ctx context.Context) CommunityJoinRequestRepoInterface
+	Create(request *entity.CommunityJoinRequest) error
+	GetById(Id uuid.UUID) (*entity.CommunityJoinRequest, error)
+	FindPending(communityId uuid.UUID, userId uuid.UUID) (*entity.CommunityJoinRequest, error)
+	// ListPending returns the community's open requests with their users loaded.
+	ListPending(communityId uuid.UUID) ([]*entity.CommunityJoinRequest, error)
+	// Approve marks a pending request approved by decidedBy and adds the
+	// membership in one transaction. It reports false when the request was
+	// no longer pending.
+	Approve(Id uuid.UUID, decidedBy uuid.UUID, membership *entity.CommunityMembership) (bool, error)
+	Reject(Id uuid.UUID, decidedBy uuid.UUID) (bool, error)
+}
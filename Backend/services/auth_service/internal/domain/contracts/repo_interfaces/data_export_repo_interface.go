@@ -0,0 +1,33 @@
+package repointerfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// ErrExportInProgress is returned by DataExportRepoInterface.Create when the
+// user already has an export pending or processing.
+var ErrExportInProgress = errors.New("export already in progress")
+
+type DataExportRepoInterface interface {
+	// WithContext returns a copy of the repository whose queries run with
+	// ctx, so they are cancelled with the request and traced under its span.
+	WithContext(ctx context.Context) DataExportRepoInterface
+	Create(export *entity.DataExport) error
+	GetById(Id uuid.UUID) (*entity.DataExport, error)
+	// ClaimNext marks the oldest pending export, or one stuck in processing
+	// since before staleBefore, as processing and returns it. It returns nil
+	// when there is nothing to do. Concurrent callers never get the same export.
+	ClaimNext(staleBefore time.Time) (*entity.DataExport, error)
+	// Requeue puts an export that was being processed back in the queue.
+	Requeue(Id uuid.UUID) error
+	MarkReady(Id uuid.UUID, completedAt time.Time, expiresAt time.Time) error
+	MarkFailed(Id uuid.UUID, reason string, expiresAt time.Time) error
+	// DeleteExpired removes exports that expired before now and returns their IDs.
+	DeleteExpired(now time.Time) ([]uuid.UUID, error)
+}
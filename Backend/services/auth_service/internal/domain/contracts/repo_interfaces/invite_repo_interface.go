@@ -0,0 +1,37 @@
+package repointerfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// ErrInviteCodeInvalid is returned by UserRepoInterface.CreateWithInvite when
+// the code does not exist, is revoked or expired, or has no uses left.
+var ErrInviteCodeInvalid = errors.New("invalid invite code")
+
+// InviteFilter selects invite codes. Nil fields match every code.
+type InviteFilter struct {
+	CreatedBy *uuid.UUID
+	Offset    int
+	Limit     int
+}
+
+type InviteRepoInterface interface {
+	// WithContext returns a copy of the repository whose queries run with
+	// ctx, so they are cancelled with the request and traced under its span.
+	WithContext(ctx context.Context) InviteRepoInterface
+	Create(invite *entity.InviteCode) error
+	GetById(Id uuid.UUID) (*entity.InviteCode, error)
+	// List returns a page of codes, newest first, with their redemptions and
+	// the redeeming users loaded, and the total number of matching codes.
+	List(filter InviteFilter) ([]*entity.InviteCode, int64, error)
+	// CountActive counts the user's codes that can still be redeemed at now.
+	CountActive(createdBy uuid.UUID, now time.Time) (int64, error)
+	// Revoke reports whether the code was found unrevoked.
+	Revoke(Id uuid.UUID) (bool, error)
+}
@@ -0,0 +1,28 @@
+package repointerfaces
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type PersonalTokenRepoInterface interface {
+	// WithContext returns a copy of the repository whose queries run with
+	// ctx, so they are cancelled with the request and traced under its span.
+	WithContext(ctx context.Context) PersonalTokenRepoInterface
+	Create(token *entity.PersonalAccessToken) error
+	GetByHash(tokenHash string) (*entity.PersonalAccessToken, error)
+	// ListActive returns the user's tokens that are neither revoked nor
+	// expired at now, newest first.
+	ListActive(userId uuid.UUID, now time.Time) ([]*entity.PersonalAccessToken, error)
+	CountActive(userId uuid.UUID, now time.Time) (int64, error)
+	// Revoke revokes one of the user's tokens and reports whether an active
+	// token was found.
+	Revoke(Id uuid.UUID, userId uuid.UUID) (bool, error)
+	// RevokeAllForUser revokes every token of the user that is not revoked yet.
+	RevokeAllForUser(userId uuid.UUID) error
+	UpdateLastUsed(Id uuid.UUID, at time.Time) error
+}
@@ -2,16 +2,90 @@ package repointerfaces
 
 import (
 	"auth/internal/domain/entity"
+	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrSessionLimitReached is returned by AddSessionWithLimit when the user
+// already has the maximum number of active sessions and eviction is off.
+var ErrSessionLimitReached = errors.New("session limit reached")
+
+// ErrRefreshTokenMismatch is returned by Renew when the session no longer
+// holds the refresh token being rotated, e.g. because a concurrent refresh
+// rotated it first.
+var ErrRefreshTokenMismatch = errors.New("refresh token mismatch")
+
+// SessionLimit caps the number of active sessions a user can hold.
+type SessionLimit struct {
+	// Max is the highest number of active sessions, including the new one.
+	Max int
+	// EvictLRU revokes the least recently used sessions to make room instead
+	// of rejecting the new one.
+	EvictLRU bool
+	// IdleSince excludes sessions unused since before this time from the
+	// count. Zero counts every unexpired, unrevoked session.
+	IdleSince time.Time
+}
+
+// SessionHistoryFilter selects sessions, active or not, by creation time.
+type SessionHistoryFilter struct {
+	// From and To bound CreatedAt (inclusive, exclusive); zero values are open.
+	From time.Time
+	To   time.Time
+	// AfterCreatedAt and AfterID continue a listing after the given session in
+	// newest-first order. A nil AfterID starts from the newest session.
+	AfterCreatedAt time.Time
+	AfterID        *uuid.UUID
+	// Limit caps the number of sessions returned by ListHistory.
+	Limit int
+	// ExcludeArchived leaves out the sessions moved to the archive.
+	ExcludeArchived bool
+}
+
+// DeviceUsage aggregates the sessions opened from one kind of device.
+type DeviceUsage struct {
+	Browser    string
+	OS         string
+	DeviceType string
+	DeviceName string
+	Sessions   int
+	LastSeen   time.Time
+}
+
+// IPUsage aggregates the sessions opened from one IP address.
+type IPUsage struct {
+	IP       string
+	Sessions int
+	LastSeen time.Time
+}
+
 type SessionRepoInterface interface {
+	// WithContext returns a copy of the repository whose queries run with
+	// ctx, so they are cancelled with the request and traced under its span.
+	WithContext(ctx context.Context) SessionRepoInterface
 	AddSession(session *entity.Session) (*entity.Session, error)
+	AddSessionWithLimit(session *entity.Session, limit SessionLimit) ([]uuid.UUID, error)
+	// GetById may leave TokenHash empty; use GetWithTokenHash when the
+	// refresh token hash is needed.
 	GetById(Id uuid.UUID) (*entity.Session, error)
+	// GetWithTokenHash loads the session, refresh token hash included, from
+	// the database.
+	GetWithTokenHash(Id uuid.UUID) (*entity.Session, error)
 	GetAll(userId uuid.UUID)([]*entity.Session, error)
-	RevokeSession(Id uuid.UUID) error
-	RevokeForAllUser(userId uuid.UUID) error
-	RevokeAllExceptCurrent(userId uuid.UUID, keepsessionId uuid.UUID) error
+	ListArchived(userId uuid.UUID) ([]*entity.SessionArchive, error)
+	ListHistory(userId uuid.UUID, filter SessionHistoryFilter) ([]*entity.Session, error)
+	SummarizeHistory(userId uuid.UUID, filter SessionHistoryFilter) ([]DeviceUsage, []IPUsage, error)
+	RevokeSession(Id uuid.UUID, reason string) error
+	RevokeForAllUser(userId uuid.UUID, reason string) error
+	RevokeAllExceptCurrent(userId uuid.UUID, keepsessionId uuid.UUID, reason string) error
 	UpdateLastUsed(Id uuid.UUID) error
+	UpdateDevice(session *entity.Session) error
+	// Renew replaces the refresh token hash oldTokenHash with tokenHash.
+	Renew(Id uuid.UUID, oldTokenHash string, tokenHash string, expiresAt time.Time) error
+	// SetActiveCommunity changes the community the session acts in; nil clears it.
+	SetActiveCommunity(Id uuid.UUID, communityId *uuid.UUID) error
+	PurgeInactive(before time.Time, archive bool, limit int) (int64, error)
 }
\ No newline at end of file
@@ -2,13 +2,64 @@ package repointerfaces
 
 import (
 	"auth/internal/domain/entity"
+	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// UserFilter selects users for the admin listing. Empty fields match everyone.
+type UserFilter struct {
+	// Query is matched case-insensitively against email, username, full name
+	// and phone number.
+	Query    string
+	Role     string
+	Status   string
+	Verified *bool
+	Offset   int
+	Limit    int
+}
+
+// AccountStatus is a status change applied by SetStatus.
+type AccountStatus struct {
+	Status    string
+	Reason    string
+	ChangedBy *uuid.UUID
+	ExpiresAt *time.Time
+}
+
+// AccountState is the part of an account checked on every authenticated
+// request.
+type AccountState struct {
+	Status                string
+	StatusReason          string
+	StatusExpiresAt       *time.Time
+	PasswordResetRequired bool
+}
+
 type UserRepoInterface interface {
+	// WithContext returns a copy of the repository whose queries run with
+	// ctx, so they are cancelled with the request and traced under its span.
+	WithContext(ctx context.Context) UserRepoInterface
 	Create(user *entity.User) (*entity.User, error)
+	// CreateWithInvite creates the user and uses up one use of the invite
+	// code in the same transaction.
+	CreateWithInvite(user *entity.User, code string, now time.Time) (*entity.User, error)
 	GetById(Id uuid.UUID) (*entity.User, error)
 	GetByEmail(email string) (*entity.User, error)
 	GetByUsername(username string) (*entity.User, error)	
+	// GetAccountState loads only the fields AuthMiddleware checks, so it can
+	// be cached without the rest of the user.
+	GetAccountState(Id uuid.UUID) (*AccountState, error)
+	UpdatePassword(Id uuid.UUID, passwordHash string) error
+	SetPasswordResetRequired(Id uuid.UUID, required bool) error
+	Search(filter UserFilter) ([]*entity.User, int64, error)
+	UpdateRole(Id uuid.UUID, role string) error
+	SetVerified(Id uuid.UUID, verified bool) error
+	SetStatus(Id uuid.UUID, status AccountStatus) error
+	LiftExpiredSuspensions(now time.Time) (int64, error)
+	ListDueDeletions(now time.Time, limit int) ([]uuid.UUID, error)
+	// Erase removes the account's personal data and returns the IDs of the
+	// data exports it deleted, whose archives are left to the caller.
+	Erase(Id uuid.UUID, pseudonym uuid.UUID, anonymise bool) ([]uuid.UUID, error)
 }
\ No newline at end of file
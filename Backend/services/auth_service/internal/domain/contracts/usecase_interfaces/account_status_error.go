@@ -0,0 +1,39 @@
+package usecaseinterfaces
+
+import (
+	"time"
+
+	"auth/internal/delivery/http/dto"
+	"auth/internal/domain/entity"
+)
+
+// AccountStatusError is returned when an account that is not active tries to
+// log in, refresh a session or make an authenticated request.
+type AccountStatusError struct {
+	Status string
+	Reason string
+	// Until is when a suspension ends; nil for permanent states.
+	Until *time.Time
+}
+
+func (e *AccountStatusError) Error() string {
+	return "account " + e.Status
+}
+
+// Response is the body sent with the 403 for this error.
+func (e *AccountStatusError) Response() dto.AccountStatusResponse {
+	message := "This account has been " + e.Status
+	switch e.Status {
+	case entity.UserStatusPendingDeletion:
+		message = "This account is scheduled for deletion; cancel the deletion to use it again"
+	case entity.UserStatusDeleted:
+		message = "This account has been deleted"
+	}
+	return dto.AccountStatusResponse{
+		Message: message,
+		Error:   e.Error(),
+		Code:    "account_" + e.Status,
+		Reason:  e.Reason,
+		Until:   e.Until,
+	}
+}
@@ -0,0 +1,25 @@
+package usecaseinterfaces
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/delivery/http/dto"
+
+	"github.com/google/uuid"
+)
+
+type AdminUsecaseInterface interface {
+	ListUsers(ctx context.Context, query *dto.AdminUserQuery) (*dto.AdminUserListResponse, error)
+	GetUser(ctx context.Context, userID uuid.UUID) (*dto.AdminUserDTO, error)
+	SuspendUser(ctx context.Context, actor *dto.AdminActor, userID uuid.UUID, reason string, until *time.Time) error
+	UnsuspendUser(ctx context.Context, actor *dto.AdminActor, userID uuid.UUID) error
+	ChangeStatus(ctx context.Context, actor *dto.AdminActor, userID uuid.UUID, status string, reason string, until *time.Time) error
+	VerifyUser(ctx context.Context, actor *dto.AdminActor, userID uuid.UUID) error
+	ChangeRole(ctx context.Context, actor *dto.AdminActor, userID uuid.UUID, role string) error
+	ForceLogout(ctx context.Context, actor *dto.AdminActor, userID uuid.UUID) error
+	// PromoteToAdmin grants the admin role without an acting admin, for
+	// bootstrapping from the command line.
+	PromoteToAdmin(ctx context.Context, identification string) (*dto.AdminUserDTO, error)
+	ListAuditLogs(ctx context.Context, query *dto.AuditLogQuery) (*dto.AuditLogListResponse, error)
+}
@@ -0,0 +1,18 @@
+package usecaseinterfaces
+
+import (
+	"context"
+
+	"auth/internal/delivery/http/dto"
+
+	"github.com/google/uuid"
+)
+
+type AuthzUsecaseInterface interface {
+	// Check evaluates a request from another service. The subject's account
+	// must be active and its roles are read from the database.
+	Check(ctx context.Context, request *dto.AuthzCheckRequest) (*dto.AuthzCheckResponse, error)
+	// Authorize evaluates a request against the claims of the caller's access
+	// token. communityID scopes the check to a community when not nil.
+	Authorize(ctx context.Context, principal *dto.AuthzPrincipal, permission string, communityID *uuid.UUID) (*dto.AuthzCheckResponse, error)
+}
@@ -0,0 +1,36 @@
+package usecaseinterfaces
+
+import (
+	"context"
+
+	"auth/internal/delivery/http/dto"
+
+	"github.com/google/uuid"
+)
+
+// CommunityUsecaseInterface manages communities and who belongs to them.
+// userID is always the user making the request; the methods check their
+// role in the community themselves.
+type CommunityUsecaseInterface interface {
+	CreateCommunity(ctx context.Context, userID uuid.UUID, request *dto.CreateCommunityRequest) (*dto.CommunityDTO, error)
+	GetCommunity(ctx context.Context, communityID uuid.UUID) (*dto.CommunityDTO, error)
+	DeleteCommunity(ctx context.Context, userID uuid.UUID, communityID uuid.UUID) error
+	ListMyCommunities(ctx context.Context, userID uuid.UUID) ([]*dto.MyCommunityDTO, error)
+
+	ListMembers(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, query *dto.CommunityMemberQuery) (*dto.CommunityMemberListResponse, error)
+	ChangeMemberRole(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, memberID uuid.UUID, role string) error
+	RemoveMember(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, memberID uuid.UUID) error
+	Leave(ctx context.Context, userID uuid.UUID, communityID uuid.UUID) error
+
+	Invite(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, request *dto.CreateInvitationRequest) (*dto.InvitationDTO, error)
+	ListInvitations(ctx context.Context, userID uuid.UUID, communityID uuid.UUID) ([]*dto.InvitationDTO, error)
+	RevokeInvitation(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, invitationID uuid.UUID) error
+	ListMyInvitations(ctx context.Context, userID uuid.UUID) ([]*dto.InvitationDTO, error)
+	AcceptInvitation(ctx context.Context, userID uuid.UUID, invitationID uuid.UUID) error
+	DeclineInvitation(ctx context.Context, userID uuid.UUID, invitationID uuid.UUID) error
+
+	RequestToJoin(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, request *dto.CreateJoinRequestRequest) (*dto.JoinRequestDTO, error)
+	ListJoinRequests(ctx context.Context, userID uuid.UUID, communityID uuid.UUID) ([]*dto.JoinRequestDTO, error)
+	ApproveJoinRequest(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, requestID uuid.UUID) error
+	RejectJoinRequest(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, requestID uuid.UUID) error
+}
@@ -0,0 +1,26 @@
+package usecaseinterfaces
+
+import (
+	"context"
+	"time"
+
+	"auth/internal/delivery/http/dto"
+
+	"github.com/google/uuid"
+)
+
+type DataExportUsecaseInterface interface {
+	RequestExport(ctx context.Context, userID uuid.UUID) (*dto.DataExportDTO, error)
+	GetExport(ctx context.Context, userID uuid.UUID, exportID uuid.UUID) (*dto.DataExportDTO, error)
+	// OpenDownload checks a signed download token and returns the path of the
+	// archive and the file name to offer it under.
+	OpenDownload(ctx context.Context, token string) (string, string, error)
+	// ProcessNext builds the next pending archive. It reports false when
+	// there was nothing to do. An export interrupted by ctx being cancelled
+	// is put back in the queue rather than failed.
+	ProcessNext(ctx context.Context) (bool, error)
+	PurgeExpired(ctx context.Context, now time.Time) (int, error)
+	// RemoveArchives deletes the archives of exports that were deleted, such
+	// as those of an erased account.
+	RemoveArchives(exportIDs []uuid.UUID)
+}
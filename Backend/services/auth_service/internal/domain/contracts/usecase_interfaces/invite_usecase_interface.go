@@ -0,0 +1,20 @@
+package usecaseinterfaces
+
+import (
+	"context"
+
+	"auth/internal/delivery/http/dto"
+
+	"github.com/google/uuid"
+)
+
+type InviteUsecaseInterface interface {
+	CreateInvite(ctx context.Context, userID uuid.UUID, request *dto.CreateInviteRequest) (*dto.InviteDTO, error)
+	// ListMyInvites pages through the user's own codes. query.CreatedBy is ignored.
+	ListMyInvites(ctx context.Context, userID uuid.UUID, query *dto.InviteQuery) (*dto.InviteListResponse, error)
+	RevokeInvite(ctx context.Context, userID uuid.UUID, inviteID uuid.UUID) error
+
+	// ListInvites and RevokeAnyInvite back the admin API.
+	ListInvites(ctx context.Context, query *dto.InviteQuery) (*dto.InviteListResponse, error)
+	RevokeAnyInvite(ctx context.Context, actor *dto.AdminActor, inviteID uuid.UUID) error
+}
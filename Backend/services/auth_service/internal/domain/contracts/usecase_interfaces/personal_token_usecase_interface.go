@@ -0,0 +1,17 @@
+package usecaseinterfaces
+
+import (
+	"context"
+
+	"auth/internal/delivery/http/dto"
+
+	"github.com/google/uuid"
+)
+
+type PersonalTokenUsecaseInterface interface {
+	CreateToken(ctx context.Context, userID uuid.UUID, request *dto.CreatePersonalTokenRequest) (*dto.CreatedPersonalTokenResponse, error)
+	ListTokens(ctx context.Context, userID uuid.UUID) ([]*dto.PersonalTokenDTO, error)
+	RevokeToken(ctx context.Context, userID uuid.UUID, tokenID uuid.UUID) error
+	// Authenticate resolves a personal access token presented to AuthMiddleware.
+	Authenticate(ctx context.Context, token string) (*dto.TokenPrincipal, error)
+}
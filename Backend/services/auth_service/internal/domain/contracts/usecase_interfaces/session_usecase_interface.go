@@ -1,16 +1,22 @@
 package usecaseinterfaces
 
 import (
+	"context"
+
 	"auth/internal/delivery/http/dto"
 
 	"github.com/google/uuid"
 )
 
 type SessionUsecaseInterface interface {
-	ListActiveSessions(userID uuid.UUID) ([]*dto.SessionResponseDTO, error)
-	GetSession(sessionID uuid.UUID) (*dto.SessionResponseDTO, error)
-	Logout(sessionID uuid.UUID) error
-	LogoutAllExcept(userID uuid.UUID, keepSessionID uuid.UUID) error
-	Refresh(refreshToken string) (string, error)
-    IsSessionActive(sessionID uuid.UUID) (bool, error)
+	ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]*dto.SessionResponseDTO, error)
+	ListSessionHistory(ctx context.Context, userID uuid.UUID, query *dto.SessionHistoryQuery) (*dto.SessionHistoryResponse, error)
+	GetSession(ctx context.Context, sessionID uuid.UUID) (*dto.SessionResponseDTO, error)
+	Logout(ctx context.Context, sessionID uuid.UUID) error
+	LogoutAllExcept(ctx context.Context, userID uuid.UUID, keepSessionID uuid.UUID) error
+	RevokeUserSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error
+	Refresh(ctx context.Context, refreshToken string, client *dto.ClientInfo) (string, string, error)
+	SwitchCommunity(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, communityID *uuid.UUID) (string, error)
+    IsSessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error)
+    CheckAccount(ctx context.Context, userID uuid.UUID) error
 }
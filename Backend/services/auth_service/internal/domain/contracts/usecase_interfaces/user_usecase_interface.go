@@ -1,14 +1,23 @@
 package usecaseinterfaces
 
 import (
+	"context"
+	"time"
+
 	"auth/internal/delivery/http/dto"
 
 	"github.com/google/uuid"
 )
 
 type UserUsecaseInterface interface {
-	Register(user *dto.RegisterUser) (*dto.UserDto, error)
-	Login(identification string, password string) (*dto.UserDto, string, string, error)
-	GetUserProfile(Id uuid.UUID) (*dto.UserDto, error)
-	IsVerifiedUser(Id uuid.UUID) (bool, error)
+	Register(ctx context.Context, user *dto.RegisterUser) (*dto.UserDto, error)
+	// RegistrationMode reports whether registration is open, invite_only or closed.
+	RegistrationMode() string
+	Login(ctx context.Context, identification string, password string, client *dto.ClientInfo) (*dto.UserDto, string, string, error)
+	GetUserProfile(ctx context.Context, Id uuid.UUID) (*dto.UserDto, error)
+	IsVerifiedUser(ctx context.Context, Id uuid.UUID) (bool, error)
+	DenyLogin(ctx context.Context, token string) error
+	ResetPassword(ctx context.Context, token string, newPassword string) error
+	RequestDeletion(ctx context.Context, userID uuid.UUID, password string, client *dto.ClientInfo) (time.Time, error)
+	CancelDeletion(ctx context.Context, identification string, password string, client *dto.ClientInfo) error
 }
\ No newline at end of file
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions recorded in AuditLog.Action.
+const (
+	AuditActionUserSuspend     = "user.suspend"
+	AuditActionUserUnsuspend   = "user.unsuspend"
+	AuditActionUserBan         = "user.ban"
+	AuditActionUserDeactivate  = "user.deactivate"
+	AuditActionUserReactivate  = "user.reactivate"
+	AuditActionUserVerify      = "user.verify"
+	AuditActionUserRole        = "user.change_role"
+	AuditActionUserLogout      = "user.force_logout"
+	AuditActionDeletionRequest = "user.request_deletion"
+	AuditActionDeletionCancel  = "user.cancel_deletion"
+	AuditActionInviteRevoke    = "invite.revoke"
+)
+
+// AuditLog records an administrative or security-relevant action. Actor and
+// target are kept as plain IDs, without foreign keys, so entries outlive the
+// accounts they mention; when an account is erased its ID is replaced by a
+// random pseudonym.
+type AuditLog struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	ActorID      uuid.UUID  `gorm:"type:uuid;index;not null"`
+	Action       string     `gorm:"index;not null"`
+	TargetUserID *uuid.UUID `gorm:"type:uuid;index"`
+	// Details is a JSON object with action-specific data, such as the old and new role.
+	Details   string `gorm:"type:jsonb;not null;default:'{}'"`
+	IP        string
+	CreatedAt time.Time `gorm:"index"`
+}
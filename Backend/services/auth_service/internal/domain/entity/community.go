@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Roles a member can hold within a community, from most to least privileged.
+const (
+	CommunityRoleOwner     = "owner"
+	CommunityRoleModerator = "moderator"
+	CommunityRoleMember    = "member"
+)
+
+// Statuses of a CommunityInvitation.
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusDeclined = "declined"
+	InvitationStatusRevoked  = "revoked"
+)
+
+// Statuses of a CommunityJoinRequest.
+const (
+	JoinRequestStatusPending  = "pending"
+	JoinRequestStatusApproved = "approved"
+	JoinRequestStatusRejected = "rejected"
+)
+
+// Community is a tenant of the platform. Users belong to communities through
+// CommunityMembership, each with their own role.
+type Community struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Name        string    `gorm:"not null"`
+	Slug        string    `gorm:"uniqueIndex;not null"`
+	Description string
+	// CreatedBy is nil once the creator's account has been deleted.
+	CreatedBy *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Creator *User `gorm:"foreignKey:CreatedBy;constraint:OnDelete:SET NULL;"`
+}
+
+type CommunityMembership struct {
+	CommunityID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID      uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	Role        string    `gorm:"not null"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Community Community `gorm:"foreignKey:CommunityID;constraint:OnDelete:CASCADE;"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+}
+
+// CommunityInvitation invites an existing user into a community with a role.
+type CommunityInvitation struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CommunityID uuid.UUID `gorm:"type:uuid;index;not null"`
+	InviteeID   uuid.UUID `gorm:"type:uuid;index;not null"`
+	InvitedBy   uuid.UUID `gorm:"type:uuid;not null"`
+	Role        string    `gorm:"not null"`
+	Status      string    `gorm:"index;not null"`
+	ExpiresAt   time.Time `gorm:"not null"`
+	CreatedAt   time.Time
+	RespondedAt *time.Time
+
+	Community Community `gorm:"foreignKey:CommunityID;constraint:OnDelete:CASCADE;"`
+	Invitee   User      `gorm:"foreignKey:InviteeID;constraint:OnDelete:CASCADE;"`
+	Inviter   User      `gorm:"foreignKey:InvitedBy;constraint:OnDelete:CASCADE;"`
+}
+
+// CommunityJoinRequest is a user asking to be let into a community. Owners
+// and moderators decide on it.
+type CommunityJoinRequest struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CommunityID uuid.UUID `gorm:"type:uuid;index;not null"`
+	UserID      uuid.UUID `gorm:"type:uuid;index;not null"`
+	Message     string
+	Status      string `gorm:"index;not null"`
+	// DecidedBy is nil until the request is decided, and again once that
+	// moderator's account has been deleted.
+	DecidedBy *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt time.Time
+	DecidedAt *time.Time
+
+	Community Community `gorm:"foreignKey:CommunityID;constraint:OnDelete:CASCADE;"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+	Decider   *User     `gorm:"foreignKey:DecidedBy;constraint:OnDelete:SET NULL;"`
+}
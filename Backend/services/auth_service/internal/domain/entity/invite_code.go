@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InviteCode lets people register while registration is invite-only. Each
+// registration with the code uses it up once.
+type InviteCode struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Code      string    `gorm:"uniqueIndex;not null"`
+	CreatedBy uuid.UUID `gorm:"type:uuid;index;not null"`
+	Note      string
+	// MaxUses caps the registrations the code allows; zero means unlimited.
+	MaxUses   int `gorm:"not null;default:1"`
+	Uses      int `gorm:"not null;default:0"`
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+
+	Creator     User                `gorm:"foreignKey:CreatedBy;constraint:OnDelete:CASCADE;"`
+	Redemptions []*InviteRedemption `gorm:"foreignKey:InviteCodeID"`
+}
+
+// InviteRedemption records who registered with an invite code.
+type InviteRedemption struct {
+	ID uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	// InviteCodeID is nil once the code was deleted along with its creator.
+	InviteCodeID *uuid.UUID `gorm:"type:uuid;index"`
+	UserID       uuid.UUID  `gorm:"type:uuid;uniqueIndex;not null"`
+	RedeemedAt   time.Time  `gorm:"not null"`
+
+	InviteCode *InviteCode `gorm:"foreignKey:InviteCodeID;constraint:OnDelete:SET NULL;"`
+	User       User        `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+}
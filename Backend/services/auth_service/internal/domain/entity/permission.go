@@ -0,0 +1,38 @@
+package entity
+
+// Permissions are the actions the authorization policy grants to roles.
+// Platform permissions come from the user's global Role; community
+// permissions come from their CommunityMembership role and only apply to
+// that community.
+const (
+	// PermissionAll is granted to administrators and matches every permission.
+	PermissionAll = "*"
+
+	PermissionUsersRead         = "users:read"
+	PermissionUsersManage       = "users:manage"
+	PermissionAuditRead         = "audit:read"
+	PermissionCommunitiesCreate = "communities:create"
+
+	PermissionCommunityRead      = "community:read"
+	PermissionCommunityUpdate    = "community:update"
+	PermissionCommunityDelete    = "community:delete"
+	PermissionMembersRead        = "members:read"
+	PermissionMembersInvite      = "members:invite"
+	PermissionMembersRemove      = "members:remove"
+	PermissionMembersBan         = "members:ban"
+	PermissionMembersManageRoles = "members:manage_roles"
+	PermissionJoinRequestsReview = "join_requests:review"
+	PermissionPostsCreate        = "posts:create"
+	PermissionPostsDelete        = "posts:delete"
+)
+
+// Permissions lists every permission except PermissionAll.
+var Permissions = []string{
+	PermissionUsersRead, PermissionUsersManage, PermissionAuditRead, PermissionCommunitiesCreate,
+	PermissionCommunityRead, PermissionCommunityUpdate, PermissionCommunityDelete,
+	PermissionMembersRead, PermissionMembersInvite, PermissionMembersRemove, PermissionMembersBan,
+	PermissionMembersManageRoles, PermissionJoinRequestsReview, PermissionPostsCreate, PermissionPostsDelete,
+}
+
+// ResourceTypeCommunity scopes a permission check to one community.
+const ResourceTypeCommunity = "community"
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersonalAccessTokenPrefix starts every personal access token, which tells
+// them apart from JWTs in the Authorization header.
+const PersonalAccessTokenPrefix = "cpat_"
+
+// Scopes that can be granted to a personal access token. Sessions created by
+// logging in hold all of them.
+const (
+	ScopeUserRead         = "user:read"
+	ScopeUserWrite        = "user:write"
+	ScopeSessionsRead     = "sessions:read"
+	ScopeSessionsWrite    = "sessions:write"
+	ScopeCommunitiesRead  = "communities:read"
+	ScopeCommunitiesWrite = "communities:write"
+	// ScopeAdmin only takes effect for users with the admin role.
+	ScopeAdmin = "admin"
+)
+
+// AllScopes lists every scope, in the order they are documented.
+var AllScopes = []string{ScopeUserRead, ScopeUserWrite, ScopeSessionsRead, ScopeSessionsWrite, ScopeCommunitiesRead, ScopeCommunitiesWrite, ScopeAdmin}
+
+// PersonalAccessToken is a long-lived API credential created by a user. Only
+// the SHA-512 hash of the token is stored, as with Session.TokenHash.
+type PersonalAccessToken struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;index;not null"`
+	Name      string    `gorm:"not null"`
+	TokenHash string    `gorm:"uniqueIndex;not null"`
+	// Hint is the start of the token, shown so users can tell tokens apart.
+	Hint string `gorm:"not null"`
+	// Scopes is a space-separated list of granted scopes.
+	Scopes     string `gorm:"not null"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+	RevokedAt  *time.Time `gorm:"index"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+}
@@ -6,6 +6,23 @@ import (
 	"github.com/google/uuid"
 )
 
+// Reasons recorded in Session.RevokedReason.
+const (
+    RevokeReasonLogout       = "logout"
+    RevokeReasonLogoutOthers = "logout_other_devices"
+    RevokeReasonUserRevoked  = "revoked_by_user"
+    RevokeReasonSessionLimit = "session_limit"
+    RevokeReasonReportedNotMe = "reported_not_me"
+    RevokeReasonPasswordReset = "password_reset"
+    RevokeReasonAdminLogout   = "admin_logout"
+    RevokeReasonSuspended     = "account_suspended"
+    RevokeReasonBanned        = "account_banned"
+    RevokeReasonDeactivated   = "account_deactivated"
+    RevokeReasonAccountDeletion = "account_deletion"
+    RevokeReasonRoleChanged   = "role_changed"
+    RevokeReasonTokenReuse    = "refresh_token_reuse"
+)
+
 type Session struct {
     ID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
     UserID     uuid.UUID  `gorm:"type:uuid;index;not null"`
@@ -13,9 +30,16 @@ type Session struct {
     ExpiresAt  time.Time  `gorm:"index;not null"`
     UserAgent  string
     IP         string
+    Browser    string
+    OS         string
+    DeviceType string
+    DeviceName string
     LastUsedAt time.Time
     CreatedAt  time.Time  `gorm:"autoCreateTime"`
-    RevokedAt  *time.Time
+    RevokedAt  *time.Time `gorm:"index"`
+    RevokedReason string
+    // ActiveCommunityID is the community the session's access tokens act in.
+    ActiveCommunityID *uuid.UUID `gorm:"type:uuid"`
 
     User       User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"` 
 }
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionArchive keeps purged sessions when the janitor runs in archive mode.
+// The refresh token hash is not carried over.
+type SessionArchive struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID `gorm:"type:uuid;index;not null"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	UserAgent  string
+	IP         string
+	Browser    string
+	OS         string
+	DeviceType string
+	DeviceName string
+	LastUsedAt time.Time
+	CreatedAt  time.Time
+	RevokedAt  *time.Time
+	RevokedReason string
+	ArchivedAt time.Time `gorm:"index;not null"`
+}
@@ -6,6 +6,27 @@ import (
 	"github.com/google/uuid"
 )
 
+// Roles a user can hold.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Account statuses stored in User.Status. Only active accounts may log in or
+// use their sessions.
+const (
+	UserStatusActive      = "active"
+	UserStatusSuspended   = "suspended"
+	UserStatusBanned      = "banned"
+	UserStatusDeactivated = "deactivated"
+	// UserStatusPendingDeletion marks an account whose owner asked for it to
+	// be deleted; it is erased once StatusExpiresAt passes.
+	UserStatusPendingDeletion = "pending_deletion"
+	// UserStatusDeleted is left on rows that were anonymised instead of deleted.
+	UserStatusDeleted = "deleted"
+)
+
 type User struct {
 	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	FullName      string    `gorm:"not null"`
@@ -17,6 +38,16 @@ type User struct {
 	Role          string    `gorm:"not null;default:user"`
 	AcceptedTerms bool      `gorm:"not null;default:false"`
 	IsVerified    bool      `gorm:"not null;default:false"`
+	PasswordResetRequired bool `gorm:"not null;default:false"`
+	Status        string    `gorm:"not null;default:active;index"`
+	// StatusReason, StatusChangedBy and StatusChangedAt describe the last
+	// status change; StatusChangedBy is nil when the system made it.
+	StatusReason    string
+	StatusChangedBy *uuid.UUID `gorm:"type:uuid"`
+	StatusChangedAt *time.Time
+	// StatusExpiresAt ends a suspension automatically, or is when an account
+	// pending deletion gets erased.
+	StatusExpiresAt *time.Time `gorm:"index"`
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// acquireScript takes the lock when it is free and extends it when this
+// holder already owns it, so the current leader keeps its lease.
+var acquireScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+// releaseScript deletes the lock only if this holder still owns it.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Lock is a lease-based Redis lock used to elect a single replica to run a
+// background job. A holder that stops renewing loses the lock once ttl passes.
+type Lock struct {
+	rdb   *redis.Client
+	key   string
+	token string
+	ttl   time.Duration
+}
+
+func NewLock(rdb *redis.Client, key string, ttl time.Duration) *Lock {
+	return &Lock{rdb: rdb, key: key, token: uuid.NewString(), ttl: ttl}
+}
+
+// TryAcquire takes or renews the lock, reporting whether this holder owns it.
+func (l *Lock) TryAcquire(ctx context.Context) (bool, error) {
+	res, err := acquireScript.Run(ctx, l.rdb, []string{l.key}, l.token, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
+
+// Release gives up the lock if this holder owns it.
+func (l *Lock) Release(ctx context.Context) error {
+	return releaseScript.Run(ctx, l.rdb, []string{l.key}, l.token).Err()
+}
@@ -2,30 +2,32 @@ package cache
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"os"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
 var ctx = context.Background()
 
-func NewRedisClient() *redis.Client {
-	addr := os.Getenv("REDIS_URL")
-	if addr == "" {
-		addr = "localhost:6379" // default
-	}
-
+func NewRedisClient(addr string, password string) *redis.Client {
 	rdb := redis.NewClient(&redis.Options{
 		Addr:     addr,
-		Password: "", // Set password if needed
-		DB:       0,  // default DB
+		Password: password,
+		DB:       0, // default DB
 	})
+	// Commands become spans under the span in their context
+	if err := redisotel.InstrumentTracing(rdb, redisotel.WithDBStatement(false)); err != nil {
+		slog.Error("Failed to set up Redis tracing", "error", err)
+		os.Exit(1)
+	}
 
 	if _, err := rdb.Ping(ctx).Result(); err != nil {
-		log.Fatalf("Failed to connect to Redis: %v", err)
+		slog.Error("Failed to connect to Redis", "addr", addr, "error", err)
+		os.Exit(1)
 	}
 
-	log.Println("Connected to Redis successfully")
+	slog.Info("Connected to Redis", "addr", addr)
 	return rdb
 }
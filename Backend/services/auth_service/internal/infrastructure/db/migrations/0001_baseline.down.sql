@@ -0,0 +1,7 @@
+-- The baseline adopts tables that may hold production data, so it is never
+-- rolled back.
+DO $$
+BEGIN
+    RAISE EXCEPTION 'the baseline migration cannot be rolled back';
+END
+$$;
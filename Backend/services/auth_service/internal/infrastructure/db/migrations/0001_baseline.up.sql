@@ -0,0 +1,38 @@
+-- Baseline: the schema GORM AutoMigrate created before migrations were
+-- introduced. Everything is created only if missing so existing databases
+-- adopt it as is; later changes are separate migrations.
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "full_name" text NOT NULL,
+    "email" text NOT NULL,
+    "username" text NOT NULL,
+    "phone_number" text NOT NULL,
+    "country" text NOT NULL,
+    "password_hash" text NOT NULL,
+    "role" text NOT NULL DEFAULT 'user',
+    "accepted_terms" boolean NOT NULL DEFAULT false,
+    "is_verified" boolean NOT NULL DEFAULT false,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_phone_number" ON "users" ("phone_number");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_username" ON "users" ("username");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+
+CREATE TABLE IF NOT EXISTS "sessions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "token_hash" text NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "user_agent" text,
+    "ip" text,
+    "last_used_at" timestamptz,
+    "created_at" timestamptz,
+    "revoked_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_refresh_sessions" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sessions_expires_at" ON "sessions" ("expires_at");
+CREATE INDEX IF NOT EXISTS "idx_sessions_user_id" ON "sessions" ("user_id");
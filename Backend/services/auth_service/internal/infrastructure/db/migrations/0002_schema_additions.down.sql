@@ -0,0 +1,27 @@
+DROP TABLE IF EXISTS "invite_redemptions";
+DROP TABLE IF EXISTS "invite_codes";
+DROP TABLE IF EXISTS "community_join_requests";
+DROP TABLE IF EXISTS "community_invitations";
+DROP TABLE IF EXISTS "community_memberships";
+DROP TABLE IF EXISTS "communities";
+DROP TABLE IF EXISTS "personal_access_tokens";
+DROP TABLE IF EXISTS "data_exports";
+DROP TABLE IF EXISTS "audit_logs";
+DROP TABLE IF EXISTS "session_archives";
+
+DROP INDEX IF EXISTS "idx_sessions_revoked_at";
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "active_community_id";
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "revoked_reason";
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "device_name";
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "device_type";
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "os";
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "browser";
+
+DROP INDEX IF EXISTS "idx_users_status";
+DROP INDEX IF EXISTS "idx_users_status_expires_at";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "status_expires_at";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "status_changed_at";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "status_changed_by";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "status_reason";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "status";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "password_reset_required";
@@ -0,0 +1,179 @@
+-- Columns and tables added since the baseline. Databases last set up by
+-- AutoMigrate may already have some of them, so each is only added if missing.
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "password_reset_required" boolean NOT NULL DEFAULT false;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "status" text NOT NULL DEFAULT 'active';
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "status_reason" text;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "status_changed_by" uuid;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "status_changed_at" timestamptz;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "status_expires_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_users_status_expires_at" ON "users" ("status_expires_at");
+CREATE INDEX IF NOT EXISTS "idx_users_status" ON "users" ("status");
+
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "browser" text;
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "os" text;
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "device_type" text;
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "device_name" text;
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "last_used_at" timestamptz;
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "revoked_reason" text;
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "active_community_id" uuid;
+CREATE INDEX IF NOT EXISTS "idx_sessions_revoked_at" ON "sessions" ("revoked_at");
+
+CREATE TABLE IF NOT EXISTS "session_archives" (
+    "id" uuid,
+    "user_id" uuid NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "user_agent" text,
+    "ip" text,
+    "browser" text,
+    "os" text,
+    "device_type" text,
+    "device_name" text,
+    "last_used_at" timestamptz,
+    "created_at" timestamptz,
+    "revoked_at" timestamptz,
+    "revoked_reason" text,
+    "archived_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_session_archives_archived_at" ON "session_archives" ("archived_at");
+CREATE INDEX IF NOT EXISTS "idx_session_archives_user_id" ON "session_archives" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "audit_logs" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "actor_id" uuid NOT NULL,
+    "action" text NOT NULL,
+    "target_user_id" uuid,
+    "details" jsonb NOT NULL DEFAULT '{}',
+    "ip" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_created_at" ON "audit_logs" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_target_user_id" ON "audit_logs" ("target_user_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_action" ON "audit_logs" ("action");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_actor_id" ON "audit_logs" ("actor_id");
+
+CREATE TABLE IF NOT EXISTS "data_exports" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "status" text NOT NULL,
+    "error" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "completed_at" timestamptz,
+    "expires_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_data_exports_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_data_exports_expires_at" ON "data_exports" ("expires_at");
+CREATE INDEX IF NOT EXISTS "idx_data_exports_status" ON "data_exports" ("status");
+CREATE INDEX IF NOT EXISTS "idx_data_exports_user_id" ON "data_exports" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "personal_access_tokens" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "name" text NOT NULL,
+    "token_hash" text NOT NULL,
+    "hint" text NOT NULL,
+    "scopes" text NOT NULL,
+    "expires_at" timestamptz,
+    "last_used_at" timestamptz,
+    "created_at" timestamptz,
+    "revoked_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_personal_access_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_personal_access_tokens_revoked_at" ON "personal_access_tokens" ("revoked_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_personal_access_tokens_token_hash" ON "personal_access_tokens" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_personal_access_tokens_user_id" ON "personal_access_tokens" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "communities" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "name" text NOT NULL,
+    "slug" text NOT NULL,
+    "description" text,
+    "created_by" uuid,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_communities_creator" FOREIGN KEY ("created_by") REFERENCES "users"("id") ON DELETE SET NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_communities_slug" ON "communities" ("slug");
+
+CREATE TABLE IF NOT EXISTS "community_memberships" (
+    "community_id" uuid,
+    "user_id" uuid,
+    "role" text NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("community_id","user_id"),
+    CONSTRAINT "fk_community_memberships_community" FOREIGN KEY ("community_id") REFERENCES "communities"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_community_memberships_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_community_memberships_user_id" ON "community_memberships" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "community_invitations" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "community_id" uuid NOT NULL,
+    "invitee_id" uuid NOT NULL,
+    "invited_by" uuid NOT NULL,
+    "role" text NOT NULL,
+    "status" text NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    "responded_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_community_invitations_community" FOREIGN KEY ("community_id") REFERENCES "communities"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_community_invitations_invitee" FOREIGN KEY ("invitee_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_community_invitations_inviter" FOREIGN KEY ("invited_by") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_community_invitations_status" ON "community_invitations" ("status");
+CREATE INDEX IF NOT EXISTS "idx_community_invitations_invitee_id" ON "community_invitations" ("invitee_id");
+CREATE INDEX IF NOT EXISTS "idx_community_invitations_community_id" ON "community_invitations" ("community_id");
+
+CREATE TABLE IF NOT EXISTS "community_join_requests" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "community_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "message" text,
+    "status" text NOT NULL,
+    "decided_by" uuid,
+    "created_at" timestamptz,
+    "decided_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_community_join_requests_community" FOREIGN KEY ("community_id") REFERENCES "communities"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_community_join_requests_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_community_join_requests_decider" FOREIGN KEY ("decided_by") REFERENCES "users"("id") ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS "idx_community_join_requests_status" ON "community_join_requests" ("status");
+CREATE INDEX IF NOT EXISTS "idx_community_join_requests_user_id" ON "community_join_requests" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_community_join_requests_community_id" ON "community_join_requests" ("community_id");
+
+CREATE TABLE IF NOT EXISTS "invite_codes" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "code" text NOT NULL,
+    "created_by" uuid NOT NULL,
+    "note" text,
+    "max_uses" bigint NOT NULL DEFAULT 1,
+    "uses" bigint NOT NULL DEFAULT 0,
+    "expires_at" timestamptz,
+    "revoked_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_invite_codes_creator" FOREIGN KEY ("created_by") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_invite_codes_created_by" ON "invite_codes" ("created_by");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_invite_codes_code" ON "invite_codes" ("code");
+
+CREATE TABLE IF NOT EXISTS "invite_redemptions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "invite_code_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "redeemed_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_invite_redemptions_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_invite_codes_redemptions" FOREIGN KEY ("invite_code_id") REFERENCES "invite_codes"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_invite_redemptions_user_id" ON "invite_redemptions" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_invite_redemptions_invite_code_id" ON "invite_redemptions" ("invite_code_id");
@@ -0,0 +1,7 @@
+ALTER TABLE "invite_redemptions" DROP CONSTRAINT IF EXISTS "fk_invite_redemptions_invite_code";
+ALTER TABLE "invite_redemptions" ADD CONSTRAINT "fk_invite_codes_redemptions"
+    FOREIGN KEY ("invite_code_id") REFERENCES "invite_codes"("id");
+
+ALTER TABLE "sessions" DROP CONSTRAINT IF EXISTS "fk_sessions_user";
+ALTER TABLE "sessions" ADD CONSTRAINT "fk_users_refresh_sessions"
+    FOREIGN KEY ("user_id") REFERENCES "users"("id");
@@ -0,0 +1,13 @@
+-- AutoMigrate created these foreign keys from the has-many side of each
+-- relation, without the ON DELETE CASCADE declared on the entities. Deleting an
+-- account relies on its sessions going with it.
+
+ALTER TABLE "sessions" DROP CONSTRAINT IF EXISTS "fk_users_refresh_sessions";
+ALTER TABLE "sessions" DROP CONSTRAINT IF EXISTS "fk_sessions_user";
+ALTER TABLE "sessions" ADD CONSTRAINT "fk_sessions_user"
+    FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+
+ALTER TABLE "invite_redemptions" DROP CONSTRAINT IF EXISTS "fk_invite_codes_redemptions";
+ALTER TABLE "invite_redemptions" DROP CONSTRAINT IF EXISTS "fk_invite_redemptions_invite_code";
+ALTER TABLE "invite_redemptions" ADD CONSTRAINT "fk_invite_redemptions_invite_code"
+    FOREIGN KEY ("invite_code_id") REFERENCES "invite_codes"("id") ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+DELETE FROM "invite_redemptions" WHERE "invite_code_id" IS NULL;
+ALTER TABLE "invite_redemptions" DROP CONSTRAINT IF EXISTS "fk_invite_redemptions_invite_code";
+ALTER TABLE "invite_redemptions" ADD CONSTRAINT "fk_invite_redemptions_invite_code"
+    FOREIGN KEY ("invite_code_id") REFERENCES "invite_codes"("id") ON DELETE CASCADE;
+ALTER TABLE "invite_redemptions" ALTER COLUMN "invite_code_id" SET NOT NULL;
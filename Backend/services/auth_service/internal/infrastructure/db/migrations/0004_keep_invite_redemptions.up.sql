@@ -0,0 +1,8 @@
+-- Deleting an account deletes the invite codes it created. Their redemptions
+-- belong to the people who registered with them, so they are kept with the
+-- code cleared instead of going with it.
+
+ALTER TABLE "invite_redemptions" ALTER COLUMN "invite_code_id" DROP NOT NULL;
+ALTER TABLE "invite_redemptions" DROP CONSTRAINT IF EXISTS "fk_invite_redemptions_invite_code";
+ALTER TABLE "invite_redemptions" ADD CONSTRAINT "fk_invite_redemptions_invite_code"
+    FOREIGN KEY ("invite_code_id") REFERENCES "invite_codes"("id") ON DELETE SET NULL;
@@ -0,0 +1,15 @@
+-- A user may only have one export pending or processing. Requests used to
+-- check for one before queueing another, which two concurrent requests could
+-- both pass; the index makes the database enforce it. Duplicates queued
+-- before it existed are failed, keeping the newest.
+
+UPDATE "data_exports" SET "status" = 'failed', "error" = 'superseded by a newer request', "expires_at" = now()
+WHERE "status" IN ('pending', 'processing')
+  AND "id" NOT IN (
+    SELECT DISTINCT ON ("user_id") "id" FROM "data_exports"
+    WHERE "status" IN ('pending', 'processing')
+    ORDER BY "user_id", "created_at" DESC
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_data_exports_user_in_progress" ON "data_exports" ("user_id")
+    WHERE "status" IN ('pending', 'processing');
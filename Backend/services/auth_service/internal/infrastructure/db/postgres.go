@@ -1,7 +1,7 @@
 package db
 
 import (
-	"log"
+	"log/slog"
 	"os"
 
 	"gorm.io/driver/postgres"
@@ -9,15 +9,15 @@ import (
 )
 
 // NewPostgresDB returns a gorm.DB instance
-func NewPostgresDB() *gorm.DB {
-	dsn := os.Getenv("DATABASE_URL")
-	if dsn == "" {
-		log.Fatal("DATABASE_URL environment variable is not set")
-	}
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+func NewPostgresDB(dsn string) *gorm.DB {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: newGormLogger()})
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		slog.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+	if err := db.Use(tracingPlugin{}); err != nil {
+		slog.Error("Failed to set up query tracing", "error", err)
+		os.Exit(1)
 	}
 
 	return db
@@ -0,0 +1,127 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"auth/internal/infrastructure/db"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Statuses of a Report and of each of its checks.
+const (
+	StatusOK           = "ok"
+	StatusUnavailable  = "unavailable"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Check is the outcome of checking one dependency.
+type Check struct {
+	Status  string
+	Latency time.Duration
+	// Err is why the dependency is unavailable. It may carry addresses and
+	// driver details, so it is logged rather than served.
+	Err error
+}
+
+// Report is the outcome of a readiness check.
+type Report struct {
+	Status string
+	// MigrationVersion is the latest migration applied to the database and
+	// LatestMigration the latest one this binary knows.
+	MigrationVersion int64
+	LatestMigration  int64
+	Checks           map[string]Check
+}
+
+// Checker reports whether the service can reach its dependencies. It reports
+// not ready once shutdown has begun, so load balancers stop routing to it.
+type Checker struct {
+	database     *gorm.DB
+	redis        *redis.Client
+	migrator     *db.Migrator
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+// NewChecker creates a Checker that gives each dependency timeout to answer.
+func NewChecker(database *gorm.DB, redis *redis.Client, migrator *db.Migrator, timeout time.Duration) *Checker {
+	return &Checker{database: database, redis: redis, migrator: migrator, timeout: timeout}
+}
+
+// SetShuttingDown makes every later readiness check fail.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Readiness pings Postgres and Redis concurrently and reads the applied
+// migration version. The service is ready when both answer, the schema is at
+// least as new as this binary expects and shutdown has not begun. Failed
+// checks are logged with their error.
+func (c *Checker) Readiness(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var (
+		wg               sync.WaitGroup
+		postgres, cache  Check
+		migrationVersion int64
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		postgres = check(func() error {
+			sqlDB, err := c.database.DB()
+			if err != nil {
+				return err
+			}
+			if err := sqlDB.PingContext(ctx); err != nil {
+				return err
+			}
+			migrationVersion, err = c.migrator.Version(ctx)
+			return err
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		cache = check(func() error {
+			return c.redis.Ping(ctx).Err()
+		})
+	}()
+	wg.Wait()
+
+	report := &Report{
+		Status:           StatusOK,
+		MigrationVersion: migrationVersion,
+		LatestMigration:  c.migrator.Latest(),
+		Checks:           map[string]Check{"postgres": postgres, "redis": cache},
+	}
+	for name, result := range report.Checks {
+		if result.Err != nil {
+			slog.Warn("Readiness check failed", "check", name, "latency", result.Latency, "error", result.Err)
+		}
+	}
+	switch {
+	case c.shuttingDown.Load():
+		report.Status = StatusShuttingDown
+	case postgres.Status != StatusOK || cache.Status != StatusOK,
+		report.MigrationVersion < report.LatestMigration:
+		report.Status = StatusUnavailable
+	}
+	return report
+}
+
+func check(ping func() error) Check {
+	start := time.Now()
+	err := ping()
+	result := Check{Status: StatusOK, Latency: time.Since(start), Err: err}
+	if err != nil {
+		result.Status = StatusUnavailable
+	}
+	return result
+}
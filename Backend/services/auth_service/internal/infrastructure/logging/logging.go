@@ -0,0 +1,132 @@
+// Package logging builds the structured logger used across the service.
+//
+// Records are written as JSON lines by default. Records logged with a request
+// context carry its request ID and trace ID. Attributes whose key names a
+// secret, such as password or refresh_token, are redacted, as are the values
+// of such parameters in URLs, so tokens never reach the logs.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeyParts marks attribute keys and URL parameters that hold
+// secrets. Keys ending in _id, such as token_id, are identifiers and are
+// kept.
+var sensitiveKeyParts = []string{"password", "secret", "token", "authorization", "cookie"}
+
+// sensitiveURLParam matches the value of a sensitive query parameter inside
+// free text, e.g. a download link in a notification body.
+var sensitiveURLParam = regexp.MustCompile(`(?i)([?&][\w-]*(?:password|secret|token)[\w-]*=)[^&\s"']+`)
+
+// New returns a logger writing records at or above level to w, as JSON or,
+// with format "text", as key=value pairs for reading in a terminal.
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var minLevel slog.Level
+	if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+	options := &slog.HandlerOptions{Level: minLevel, ReplaceAttr: redactAttr}
+
+	var handler slog.Handler
+	switch format {
+	case "json":
+		handler = slog.NewJSONHandler(w, options)
+	case "text":
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("invalid log format %q", format)
+	}
+	return slog.New(contextHandler{handler}), nil
+}
+
+// IsSensitive reports whether an attribute, header or parameter named key
+// holds a secret.
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	if strings.HasSuffix(key, "_id") {
+		return false
+	}
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactQuery returns an encoded query string with the values of sensitive
+// parameters redacted.
+func RedactQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return redacted
+	}
+	for key := range values {
+		if IsSensitive(key) {
+			values[key] = []string{redacted}
+		}
+	}
+	return values.Encode()
+}
+
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	if IsSensitive(attr.Key) {
+		return slog.String(attr.Key, redacted)
+	}
+	if attr.Value.Kind() == slog.KindString {
+		value := attr.Value.String()
+		if strings.Contains(value, "=") {
+			return slog.String(attr.Key, sensitiveURLParam.ReplaceAllString(value, "${1}"+redacted))
+		}
+	}
+	return attr
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request being
+// served. Records logged with that context include it.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "" if there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler adds the request ID and the trace and span IDs carried by the
+// context to each record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
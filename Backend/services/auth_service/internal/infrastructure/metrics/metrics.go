@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "auth"
+
+var (
+	// SessionsPurged counts session rows removed by the janitor, by mode (delete or archive).
+	SessionsPurged = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sessions_purged_total",
+		Help:      "Expired and revoked sessions removed by the session janitor.",
+	}, []string{"mode"})
+
+	// JanitorRuns counts janitor runs on the leader replica, by result (success or error).
+	JanitorRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "session_janitor_runs_total",
+		Help:      "Session janitor runs, by result.",
+	}, []string{"result"})
+
+	// JanitorLastSuccess is the Unix time of the last successful janitor run.
+	JanitorLastSuccess = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "session_janitor_last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful session janitor run.",
+	})
+
+	// HTTPRequestDuration observes served requests by method, route template
+	// and status code.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests, by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestsInFlight is the number of requests being served.
+	HTTPRequestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+
+	// RateLimitRejections counts requests answered with 429 Too Many Requests,
+	// by route.
+	RateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Requests rejected with 429 Too Many Requests, by route.",
+	}, []string{"route"})
+
+	// Logins counts password logins by outcome.
+	Logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Password logins, by outcome.",
+	}, []string{"outcome"})
+
+	// Refreshes counts refresh token exchanges by outcome.
+	Refreshes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "token_refreshes_total",
+		Help:      "Refresh token exchanges, by outcome.",
+	}, []string{"outcome"})
+
+	// Registrations counts sign-ups by outcome.
+	Registrations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registrations_total",
+		Help:      "Account registrations, by outcome.",
+	}, []string{"outcome"})
+
+	// SessionsRevoked counts revoked sessions by revocation reason.
+	SessionsRevoked = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sessions_revoked_total",
+		Help:      "Revoked sessions, by reason.",
+	}, []string{"reason"})
+
+	// PasswordHashDuration observes bcrypt hashing and comparison, by
+	// operation (hash or compare).
+	PasswordHashDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "password_hash_duration_seconds",
+		Help:      "Duration of bcrypt password hashing and comparison, by operation.",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation"})
+)
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/redis/go-redis/v9"
+)
+
+// RegisterPoolStats exports the connection pool statistics of the Postgres
+// and Redis clients, read at scrape time.
+func RegisterPoolStats(db *sql.DB, client *redis.Client) {
+	prometheus.MustRegister(
+		collectors.NewDBStatsCollector(db, "postgres"),
+		newRedisPoolCollector(client),
+	)
+}
+
+var (
+	redisPoolHits     = redisPoolDesc("hits_total", "Times a free connection was found in the pool.")
+	redisPoolMisses   = redisPoolDesc("misses_total", "Times a free connection was not found in the pool.")
+	redisPoolTimeouts = redisPoolDesc("timeouts_total", "Times a wait for a connection timed out.")
+	redisPoolTotal    = redisPoolDesc("connections", "Connections in the pool.")
+	redisPoolIdle     = redisPoolDesc("idle_connections", "Idle connections in the pool.")
+	redisPoolStale    = redisPoolDesc("stale_connections_total", "Stale connections removed from the pool.")
+)
+
+func redisPoolDesc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "redis_pool", name), help, nil, nil)
+}
+
+// redisPoolCollector reports redis.Client.PoolStats.
+type redisPoolCollector struct {
+	client *redis.Client
+}
+
+func newRedisPoolCollector(client *redis.Client) prometheus.Collector {
+	return redisPoolCollector{client: client}
+}
+
+func (c redisPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- redisPoolHits
+	ch <- redisPoolMisses
+	ch <- redisPoolTimeouts
+	ch <- redisPoolTotal
+	ch <- redisPoolIdle
+	ch <- redisPoolStale
+}
+
+func (c redisPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(redisPoolHits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(redisPoolMisses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(redisPoolTimeouts, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(redisPoolTotal, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(redisPoolIdle, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(redisPoolStale, prometheus.CounterValue, float64(stats.StaleConns))
+}
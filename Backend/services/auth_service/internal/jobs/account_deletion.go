@@ -0,0 +1,84 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+
+	"github.com/google/uuid"
+)
+
+// Deletion modes supported by AccountDeletion.
+const (
+	DeletionModeDelete    = "delete"
+	DeletionModeAnonymise = "anonymise"
+)
+
+// deletionBatchSize bounds how many accounts are erased per run.
+const deletionBatchSize = 100
+
+// AccountDeletion erases accounts whose deletion grace period has ended,
+// either deleting the user row or anonymising it, and removes their data
+// export archives. Erasing an account twice is harmless, so every replica may
+// run it without coordination.
+type AccountDeletion struct {
+	repo     repointerfaces.UserRepoInterface
+	exports  usecaseinterfaces.DataExportUsecaseInterface
+	interval time.Duration
+	mode     string
+}
+
+func NewAccountDeletion(repo repointerfaces.UserRepoInterface, exports usecaseinterfaces.DataExportUsecaseInterface, interval time.Duration, mode string) *AccountDeletion {
+	return &AccountDeletion{repo: repo, exports: exports, interval: interval, mode: mode}
+}
+
+// Run erases due accounts on every interval until ctx is cancelled.
+func (j *AccountDeletion) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	slog.Info("Account deletion job started", "interval", j.interval.String(), "mode", j.mode)
+	for {
+		j.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *AccountDeletion) runOnce(ctx context.Context) {
+	for ctx.Err() == nil {
+		ids, err := j.repo.ListDueDeletions(time.Now().UTC(), deletionBatchSize)
+		if err != nil {
+			slog.Error("Failed to list accounts due for deletion", "error", err)
+			return
+		}
+
+		erased := 0
+		for _, id := range ids {
+			// A fresh pseudonym per account keeps its audit entries linked to
+			// each other but not to the person.
+			exportIDs, err := j.repo.Erase(id, uuid.New(), j.mode == DeletionModeAnonymise)
+			if err != nil {
+				slog.Error("Failed to erase account", "user_id", id, "error", err)
+				continue
+			}
+			j.exports.RemoveArchives(exportIDs)
+			erased++
+		}
+		if erased > 0 {
+			slog.Info("Erased accounts", "count", erased, "mode", j.mode)
+		}
+		// Stop when the batch was short, or nothing could be erased, so one
+		// failing account does not keep the loop spinning.
+		if len(ids) < deletionBatchSize || erased == 0 {
+			return
+		}
+	}
+}
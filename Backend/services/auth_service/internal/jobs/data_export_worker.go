@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+)
+
+// DataExportWorker builds queued personal data exports and removes expired
+// ones. Exports are claimed with row locks, so any number of replicas can run it.
+type DataExportWorker struct {
+	usecase  usecaseinterfaces.DataExportUsecaseInterface
+	interval time.Duration
+}
+
+func NewDataExportWorker(usecase usecaseinterfaces.DataExportUsecaseInterface, interval time.Duration) *DataExportWorker {
+	return &DataExportWorker{usecase: usecase, interval: interval}
+}
+
+// Run polls for work on every interval until ctx is cancelled.
+func (w *DataExportWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *DataExportWorker) runOnce(ctx context.Context) {
+	if purged, err := w.usecase.PurgeExpired(ctx, time.Now().UTC()); err != nil {
+		slog.Error("Failed to purge expired data exports", "error", err)
+	} else if purged > 0 {
+		slog.Info("Purged expired data exports", "count", purged)
+	}
+
+	for ctx.Err() == nil {
+		processed, err := w.usecase.ProcessNext(ctx)
+		if err != nil {
+			slog.Error("Data export worker failed", "error", err)
+			return
+		}
+		if !processed {
+			return
+		}
+	}
+}
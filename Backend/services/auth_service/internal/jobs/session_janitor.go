@@ -0,0 +1,107 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	"auth/internal/infrastructure/cache"
+	"auth/internal/infrastructure/metrics"
+)
+
+// Purge modes supported by SessionJanitor.
+const (
+	PurgeModeDelete  = "delete"
+	PurgeModeArchive = "archive"
+)
+
+// purgeBatchSize bounds how many rows a single statement removes, keeping
+// locks and transaction sizes small on large tables.
+const purgeBatchSize = 1000
+
+// SessionJanitorConfig controls the session janitor.
+type SessionJanitorConfig struct {
+	// Interval between runs.
+	Interval time.Duration
+	// Retention is how long revoked and expired sessions are kept before purging.
+	Retention time.Duration
+	// Mode is PurgeModeDelete or PurgeModeArchive.
+	Mode string
+}
+
+// SessionJanitor periodically purges sessions that were revoked or expired
+// longer than the retention window ago. Every replica runs it, but a Redis
+// lock ensures only the current leader does any work.
+type SessionJanitor struct {
+	repo   repointerfaces.SessionRepoInterface
+	lock   *cache.Lock
+	config SessionJanitorConfig
+}
+
+func NewSessionJanitor(repo repointerfaces.SessionRepoInterface, lock *cache.Lock, config SessionJanitorConfig) *SessionJanitor {
+	return &SessionJanitor{repo: repo, lock: lock, config: config}
+}
+
+// Run purges on every interval until ctx is cancelled, then gives up leadership.
+func (j *SessionJanitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	slog.Info("Session janitor started", "interval", j.config.Interval.String(), "retention", j.config.Retention.String(), "mode", j.config.Mode)
+	for {
+		j.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			releaseCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+			if err := j.lock.Release(releaseCtx); err != nil {
+				slog.Error("Session janitor failed to release leadership", "error", err)
+			}
+			cancel()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *SessionJanitor) runOnce(ctx context.Context) {
+	leader, err := j.lock.TryAcquire(ctx)
+	if err != nil {
+		slog.Error("Session janitor could not reach the leader lock", "error", err)
+		return
+	}
+	if !leader {
+		return
+	}
+
+	purged, err := j.purge(ctx, time.Now().UTC().Add(-j.config.Retention))
+	metrics.SessionsPurged.WithLabelValues(j.config.Mode).Add(float64(purged))
+	if err != nil {
+		metrics.JanitorRuns.WithLabelValues("error").Inc()
+		slog.Error("Session janitor failed", "purged", purged, "error", err)
+		return
+	}
+
+	metrics.JanitorRuns.WithLabelValues("success").Inc()
+	metrics.JanitorLastSuccess.SetToCurrentTime()
+	if purged > 0 {
+		slog.Info("Session janitor purged sessions", "count", purged, "mode", j.config.Mode)
+	}
+}
+
+// purge removes batches until one comes back short or ctx is cancelled.
+func (j *SessionJanitor) purge(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	for ctx.Err() == nil {
+		n, err := j.repo.PurgeInactive(before, j.config.Mode == PurgeModeArchive, purgeBatchSize)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < purgeBatchSize {
+			break
+		}
+	}
+	return total, nil
+}
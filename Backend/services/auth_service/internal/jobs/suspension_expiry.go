@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+)
+
+// SuspensionExpiry reactivates accounts whose suspension has run out. Expired
+// suspensions are already ignored at login; this keeps the stored status, and
+// so the admin listing, in line. Lifting is idempotent, so every replica may
+// run it without coordination.
+type SuspensionExpiry struct {
+	repo     repointerfaces.UserRepoInterface
+	interval time.Duration
+}
+
+func NewSuspensionExpiry(repo repointerfaces.UserRepoInterface, interval time.Duration) *SuspensionExpiry {
+	return &SuspensionExpiry{repo: repo, interval: interval}
+}
+
+// Run lifts expired suspensions on every interval until ctx is cancelled.
+func (j *SuspensionExpiry) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		lifted, err := j.repo.LiftExpiredSuspensions(time.Now().UTC())
+		if err != nil {
+			slog.Error("Failed to lift expired suspensions", "error", err)
+		} else if lifted > 0 {
+			slog.Info("Lifted expired suspensions", "count", lifted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
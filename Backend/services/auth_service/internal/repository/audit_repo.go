@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	"auth/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepo struct {
+	db *gorm.DB
+}
+
+func NewAuditRepo(db *gorm.DB) repointerfaces.AuditRepoInterface {
+	return &AuditRepo{db: db}
+}
+
+func (repo *AuditRepo) WithContext(ctx context.Context) repointerfaces.AuditRepoInterface {
+	return &AuditRepo{db: repo.db.WithContext(ctx)}
+}
+
+func (repo *AuditRepo) Create(entry *entity.AuditLog) error {
+	return repo.db.Create(entry).Error
+}
+
+// List returns one page of entries matching filter, newest first, together
+// with the total number of matches.
+func (repo *AuditRepo) List(filter repointerfaces.AuditFilter) ([]*entity.AuditLog, int64, error) {
+	query := repo.db.Model(&entity.AuditLog{})
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.TargetUserID != nil {
+		query = query.Where("target_user_id = ?", *filter.TargetUserID)
+	}
+	if filter.SubjectID != nil {
+		query = query.Where("actor_id = ? OR target_user_id = ?", *filter.SubjectID, *filter.SubjectID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []*entity.AuditLog
+	err := query.Order("created_at DESC, id").Offset(filter.Offset).Limit(filter.Limit).Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
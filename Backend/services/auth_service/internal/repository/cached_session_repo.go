@@ -0,0 +1,385 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// sessionCachePrefix is versioned so entries in an older format are
+	// never read back.
+	sessionCachePrefix = "auth:session:v2:"
+	// revokedSessionMaxTTL bounds how long a revoked session is kept in the
+	// negative cache; revoked sessions never become active again, so this only
+	// limits memory use.
+	revokedSessionMaxTTL = 24 * time.Hour
+	// cacheOpTimeout keeps a slow or unreachable Redis from delaying requests
+	// before we fall back to Postgres.
+	cacheOpTimeout = 100 * time.Millisecond
+	// revocationAttempts is how many times a revocation is written to the
+	// cache before giving up on it.
+	revocationAttempts   = 3
+	revocationRetryDelay = 50 * time.Millisecond
+)
+
+// fillSessionScript caches an active session unless the key already holds a
+// revoked one.
+var fillSessionScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if current and cjson.decode(current).revoked then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1
+`)
+
+// dropSessionScript removes a cached session unless it is a revoked one.
+var dropSessionScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if current and cjson.decode(current).revoked then
+	return 0
+end
+return redis.call('DEL', KEYS[1])
+`)
+
+// cachedSession is what the cache holds for a session. It leaves out the
+// refresh token hash, which the request path never needs.
+type cachedSession struct {
+	ID                uuid.UUID  `json:"id"`
+	UserID            uuid.UUID  `json:"user_id"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	UserAgent         string     `json:"user_agent"`
+	IP                string     `json:"ip"`
+	Browser           string     `json:"browser"`
+	OS                string     `json:"os"`
+	DeviceType        string     `json:"device_type"`
+	DeviceName        string     `json:"device_name"`
+	LastUsedAt        time.Time  `json:"last_used_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	RevokedAt         *time.Time `json:"revoked_at"`
+	RevokedReason     string     `json:"revoked_reason"`
+	ActiveCommunityID *uuid.UUID `json:"active_community_id"`
+	// Revoked is read by the cache scripts.
+	Revoked bool `json:"revoked"`
+}
+
+func newCachedSession(session *entity.Session) cachedSession {
+	return cachedSession{
+		ID:                session.ID,
+		UserID:            session.UserID,
+		ExpiresAt:         session.ExpiresAt,
+		UserAgent:         session.UserAgent,
+		IP:                session.IP,
+		Browser:           session.Browser,
+		OS:                session.OS,
+		DeviceType:        session.DeviceType,
+		DeviceName:        session.DeviceName,
+		LastUsedAt:        session.LastUsedAt,
+		CreatedAt:         session.CreatedAt,
+		RevokedAt:         session.RevokedAt,
+		RevokedReason:     session.RevokedReason,
+		ActiveCommunityID: session.ActiveCommunityID,
+		Revoked:           session.RevokedAt != nil,
+	}
+}
+
+func (cached cachedSession) session() *entity.Session {
+	return &entity.Session{
+		ID:                cached.ID,
+		UserID:            cached.UserID,
+		ExpiresAt:         cached.ExpiresAt,
+		UserAgent:         cached.UserAgent,
+		IP:                cached.IP,
+		Browser:           cached.Browser,
+		OS:                cached.OS,
+		DeviceType:        cached.DeviceType,
+		DeviceName:        cached.DeviceName,
+		LastUsedAt:        cached.LastUsedAt,
+		CreatedAt:         cached.CreatedAt,
+		RevokedAt:         cached.RevokedAt,
+		RevokedReason:     cached.RevokedReason,
+		ActiveCommunityID: cached.ActiveCommunityID,
+	}
+}
+
+// CachedSessionRepository decorates a SessionRepoInterface with a Redis cache
+// for GetById, which AuthMiddleware calls on every protected request.
+//
+// Active sessions are cached for ttl and dropped whenever they are written.
+// Revoked sessions are written through to the cache as soon as they are
+// revoked and kept until they expire, so checks against them never reach
+// Postgres. Any Redis failure is logged and the call falls through to the
+// wrapped repository.
+//
+// Fills and invalidations never replace a revoked entry, so a database read
+// that raced a revocation cannot put the active copy back. Refresh token
+// hashes are not cached; GetWithTokenHash always reads from the database.
+//
+// Revocations are retried, but if Redis keeps failing a stale active entry
+// can outlive the revocation. It is served for at most ttl, so ttl bounds how
+// long a revoked session keeps authenticating while Redis is misbehaving.
+type CachedSessionRepository struct {
+	next repointerfaces.SessionRepoInterface
+	rdb  *redis.Client
+	ttl  time.Duration
+	// ctx parents cache operations. It is never cancelled, so invalidations
+	// still happen when the request is.
+	ctx context.Context
+}
+
+func NewCachedSessionRepository(next repointerfaces.SessionRepoInterface, rdb *redis.Client, ttl time.Duration) repointerfaces.SessionRepoInterface {
+	return &CachedSessionRepository{next: next, rdb: rdb, ttl: ttl, ctx: context.Background()}
+}
+
+func (repo *CachedSessionRepository) WithContext(ctx context.Context) repointerfaces.SessionRepoInterface {
+	return &CachedSessionRepository{next: repo.next.WithContext(ctx), rdb: repo.rdb, ttl: repo.ttl, ctx: context.WithoutCancel(ctx)}
+}
+
+func (repo *CachedSessionRepository) AddSession(session *entity.Session) (*entity.Session, error) {
+	return repo.next.AddSession(session)
+}
+
+func (repo *CachedSessionRepository) AddSessionWithLimit(session *entity.Session, limit repointerfaces.SessionLimit) ([]uuid.UUID, error) {
+	evicted, err := repo.next.AddSessionWithLimit(session, limit)
+	for _, Id := range evicted {
+		repo.storeRevokedById(Id)
+	}
+	return evicted, err
+}
+
+func (repo *CachedSessionRepository) GetAll(userId uuid.UUID) ([]*entity.Session, error) {
+	return repo.next.GetAll(userId)
+}
+
+func (repo *CachedSessionRepository) GetById(Id uuid.UUID) (*entity.Session, error) {
+	if session, ok := repo.load(Id); ok {
+		return session, nil
+	}
+
+	session, err := repo.next.GetById(Id)
+	if err != nil {
+		return nil, err
+	}
+	repo.store(session)
+	return session, nil
+}
+
+func (repo *CachedSessionRepository) GetWithTokenHash(Id uuid.UUID) (*entity.Session, error) {
+	return repo.next.GetWithTokenHash(Id)
+}
+
+func (repo *CachedSessionRepository) ListArchived(userId uuid.UUID) ([]*entity.SessionArchive, error) {
+	return repo.next.ListArchived(userId)
+}
+
+func (repo *CachedSessionRepository) ListHistory(userId uuid.UUID, filter repointerfaces.SessionHistoryFilter) ([]*entity.Session, error) {
+	return repo.next.ListHistory(userId, filter)
+}
+
+func (repo *CachedSessionRepository) SummarizeHistory(userId uuid.UUID, filter repointerfaces.SessionHistoryFilter) ([]repointerfaces.DeviceUsage, []repointerfaces.IPUsage, error) {
+	return repo.next.SummarizeHistory(userId, filter)
+}
+
+func (repo *CachedSessionRepository) RevokeSession(Id uuid.UUID, reason string) error {
+	if err := repo.next.RevokeSession(Id, reason); err != nil {
+		return err
+	}
+	repo.storeRevokedById(Id)
+	return nil
+}
+
+func (repo *CachedSessionRepository) RevokeForAllUser(userId uuid.UUID, reason string) error {
+	sessions, err := repo.next.GetAll(userId)
+	if err != nil {
+		return err
+	}
+	if err := repo.next.RevokeForAllUser(userId, reason); err != nil {
+		return err
+	}
+	repo.storeRevoked(sessions, reason)
+	return nil
+}
+
+func (repo *CachedSessionRepository) RevokeAllExceptCurrent(userId uuid.UUID, keepSessionId uuid.UUID, reason string) error {
+	sessions, err := repo.next.GetAll(userId)
+	if err != nil {
+		return err
+	}
+	if err := repo.next.RevokeAllExceptCurrent(userId, keepSessionId, reason); err != nil {
+		return err
+	}
+
+	revoked := sessions[:0]
+	for _, session := range sessions {
+		if session.ID != keepSessionId {
+			revoked = append(revoked, session)
+		}
+	}
+	repo.storeRevoked(revoked, reason)
+	return nil
+}
+
+func (repo *CachedSessionRepository) UpdateLastUsed(Id uuid.UUID) error {
+	err := repo.next.UpdateLastUsed(Id)
+	repo.invalidate(Id)
+	return err
+}
+
+func (repo *CachedSessionRepository) UpdateDevice(session *entity.Session) error {
+	err := repo.next.UpdateDevice(session)
+	repo.invalidate(session.ID)
+	return err
+}
+
+func (repo *CachedSessionRepository) Renew(Id uuid.UUID, oldTokenHash string, tokenHash string, expiresAt time.Time) error {
+	err := repo.next.Renew(Id, oldTokenHash, tokenHash, expiresAt)
+	repo.invalidate(Id)
+	return err
+}
+
+func (repo *CachedSessionRepository) SetActiveCommunity(Id uuid.UUID, communityId *uuid.UUID) error {
+	err := repo.next.SetActiveCommunity(Id, communityId)
+	repo.invalidate(Id)
+	return err
+}
+
+// PurgeInactive needs no invalidation: purged sessions are revoked or expired,
+// and cached copies of them already report that until their TTL runs out.
+func (repo *CachedSessionRepository) PurgeInactive(before time.Time, archive bool, limit int) (int64, error) {
+	return repo.next.PurgeInactive(before, archive, limit)
+}
+
+func (repo *CachedSessionRepository) load(Id uuid.UUID) (*entity.Session, bool) {
+	ctx, cancel := context.WithTimeout(repo.ctx, cacheOpTimeout)
+	defer cancel()
+
+	data, err := repo.rdb.Get(ctx, sessionCacheKey(Id)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			slog.WarnContext(repo.ctx, "Session cache read failed, falling back to database", "error", err)
+		}
+		return nil, false
+	}
+
+	var cached cachedSession
+	if err := json.Unmarshal(data, &cached); err != nil {
+		slog.WarnContext(repo.ctx, "Discarding unreadable cached session", "session_id", Id, "error", err)
+		ctx, cancel := context.WithTimeout(repo.ctx, cacheOpTimeout)
+		defer cancel()
+		if err := repo.rdb.Del(ctx, sessionCacheKey(Id)).Err(); err != nil {
+			slog.ErrorContext(repo.ctx, "Session cache invalidation failed", "session_id", Id, "error", err)
+		}
+		return nil, false
+	}
+	return cached.session(), true
+}
+
+// store caches a session just read from the database. Revoked sessions are
+// written as they are; active ones only when the key does not already hold
+// a revoked entry, since the read may predate a revocation.
+func (repo *CachedSessionRepository) store(session *entity.Session) {
+	ctx, cancel := context.WithTimeout(repo.ctx, cacheOpTimeout)
+	defer cancel()
+
+	var err error
+	if session.RevokedAt != nil {
+		err = repo.set(ctx, session)
+	} else {
+		var data []byte
+		if data, err = json.Marshal(newCachedSession(session)); err == nil {
+			err = fillSessionScript.Run(ctx, repo.rdb, []string{sessionCacheKey(session.ID)}, data, repo.ttl.Milliseconds()).Err()
+		}
+	}
+	if err != nil {
+		slog.WarnContext(repo.ctx, "Session cache write failed", "session_id", session.ID, "error", err)
+	}
+}
+
+// set caches a revoked session until it would have expired anyway,
+// replacing whatever the cache holds for it. Sessions with no time left are
+// removed from the cache instead.
+func (repo *CachedSessionRepository) set(ctx context.Context, session *entity.Session) error {
+	ttl := time.Until(session.ExpiresAt)
+	if ttl > revokedSessionMaxTTL {
+		ttl = revokedSessionMaxTTL
+	}
+	if ttl <= 0 {
+		return repo.rdb.Del(ctx, sessionCacheKey(session.ID)).Err()
+	}
+
+	data, err := json.Marshal(newCachedSession(session))
+	if err != nil {
+		return err
+	}
+	return repo.rdb.Set(ctx, sessionCacheKey(session.ID), data, ttl).Err()
+}
+
+// storeRevokedById records a session that was just revoked in the database
+// as revoked in the cache, reading it back to know when it expires.
+func (repo *CachedSessionRepository) storeRevokedById(Id uuid.UUID) {
+	session, err := repo.next.GetById(Id)
+	if err != nil {
+		repo.recordRevocation(Id, func(ctx context.Context) error {
+			return repo.rdb.Del(ctx, sessionCacheKey(Id)).Err()
+		})
+		return
+	}
+	repo.recordRevocation(Id, func(ctx context.Context) error {
+		return repo.set(ctx, session)
+	})
+}
+
+// storeRevoked marks sessions that were just revoked in the database as
+// revoked in the cache.
+func (repo *CachedSessionRepository) storeRevoked(sessions []*entity.Session, reason string) {
+	now := time.Now()
+	for _, session := range sessions {
+		session.RevokedAt = &now
+		session.RevokedReason = reason
+		repo.recordRevocation(session.ID, func(ctx context.Context) error {
+			return repo.set(ctx, session)
+		})
+	}
+}
+
+// recordRevocation runs write, which replaces the cached copy of a session
+// that was just revoked, retrying it a few times when Redis fails.
+func (repo *CachedSessionRepository) recordRevocation(Id uuid.UUID, write func(ctx context.Context) error) {
+	var err error
+	for attempt := 1; attempt <= revocationAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(repo.ctx, cacheOpTimeout)
+		err = write(ctx)
+		cancel()
+		if err == nil {
+			return
+		}
+		if attempt < revocationAttempts {
+			time.Sleep(time.Duration(attempt) * revocationRetryDelay)
+		}
+	}
+	slog.ErrorContext(repo.ctx, "Failed to record session revocation in cache, a cached copy may stay active until it expires",
+		"session_id", Id, "cache_ttl", repo.ttl, "error", err)
+}
+
+// invalidate drops the cached copy of a session that was just written,
+// keeping it when it is a revoked one.
+func (repo *CachedSessionRepository) invalidate(Id uuid.UUID) {
+	ctx, cancel := context.WithTimeout(repo.ctx, cacheOpTimeout)
+	defer cancel()
+	if err := dropSessionScript.Run(ctx, repo.rdb, []string{sessionCacheKey(Id)}).Err(); err != nil {
+		slog.ErrorContext(repo.ctx, "Session cache invalidation failed", "session_id", Id, "error", err)
+	}
+}
+
+func sessionCacheKey(Id uuid.UUID) string {
+	return sessionCachePrefix + Id.String()
+}
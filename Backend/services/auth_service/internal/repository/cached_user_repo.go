@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const accountCachePrefix = "auth:account:"
+
+// CachedUserRepository decorates a UserRepoInterface with a Redis cache for
+// GetAccountState, which AuthMiddleware calls on every protected request.
+// Only the account state is cached, never the rest of the user.
+//
+// Every write that changes the state drops the cached copy. A drop that
+// keeps failing is logged, and the stale state is then served for at most
+// ttl. LiftExpiredSuspensions needs no invalidation: a cached suspension
+// that has run out already counts as active.
+type CachedUserRepository struct {
+	next repointerfaces.UserRepoInterface
+	rdb  *redis.Client
+	ttl  time.Duration
+	// ctx parents cache operations. It is never cancelled, so invalidations
+	// still happen when the request is.
+	ctx context.Context
+}
+
+func NewCachedUserRepository(next repointerfaces.UserRepoInterface, rdb *redis.Client, ttl time.Duration) repointerfaces.UserRepoInterface {
+	return &CachedUserRepository{next: next, rdb: rdb, ttl: ttl, ctx: context.Background()}
+}
+
+func (repo *CachedUserRepository) WithContext(ctx context.Context) repointerfaces.UserRepoInterface {
+	return &CachedUserRepository{next: repo.next.WithContext(ctx), rdb: repo.rdb, ttl: repo.ttl, ctx: context.WithoutCancel(ctx)}
+}
+
+func (repo *CachedUserRepository) Create(user *entity.User) (*entity.User, error) {
+	return repo.next.Create(user)
+}
+
+func (repo *CachedUserRepository) CreateWithInvite(user *entity.User, code string, now time.Time) (*entity.User, error) {
+	return repo.next.CreateWithInvite(user, code, now)
+}
+
+func (repo *CachedUserRepository) GetById(Id uuid.UUID) (*entity.User, error) {
+	return repo.next.GetById(Id)
+}
+
+func (repo *CachedUserRepository) GetByEmail(email string) (*entity.User, error) {
+	return repo.next.GetByEmail(email)
+}
+
+func (repo *CachedUserRepository) GetByUsername(username string) (*entity.User, error) {
+	return repo.next.GetByUsername(username)
+}
+
+func (repo *CachedUserRepository) GetAccountState(Id uuid.UUID) (*repointerfaces.AccountState, error) {
+	if state, ok := repo.load(Id); ok {
+		return state, nil
+	}
+
+	state, err := repo.next.GetAccountState(Id)
+	if err != nil {
+		return nil, err
+	}
+	repo.store(Id, state)
+	return state, nil
+}
+
+func (repo *CachedUserRepository) UpdatePassword(Id uuid.UUID, passwordHash string) error {
+	err := repo.next.UpdatePassword(Id, passwordHash)
+	repo.invalidate(Id)
+	return err
+}
+
+func (repo *CachedUserRepository) SetPasswordResetRequired(Id uuid.UUID, required bool) error {
+	err := repo.next.SetPasswordResetRequired(Id, required)
+	repo.invalidate(Id)
+	return err
+}
+
+func (repo *CachedUserRepository) Search(filter repointerfaces.UserFilter) ([]*entity.User, int64, error) {
+	return repo.next.Search(filter)
+}
+
+func (repo *CachedUserRepository) UpdateRole(Id uuid.UUID, role string) error {
+	return repo.next.UpdateRole(Id, role)
+}
+
+func (repo *CachedUserRepository) SetVerified(Id uuid.UUID, verified bool) error {
+	return repo.next.SetVerified(Id, verified)
+}
+
+func (repo *CachedUserRepository) SetStatus(Id uuid.UUID, status repointerfaces.AccountStatus) error {
+	err := repo.next.SetStatus(Id, status)
+	repo.invalidate(Id)
+	return err
+}
+
+func (repo *CachedUserRepository) LiftExpiredSuspensions(now time.Time) (int64, error) {
+	return repo.next.LiftExpiredSuspensions(now)
+}
+
+func (repo *CachedUserRepository) ListDueDeletions(now time.Time, limit int) ([]uuid.UUID, error) {
+	return repo.next.ListDueDeletions(now, limit)
+}
+
+func (repo *CachedUserRepository) Erase(Id uuid.UUID, pseudonym uuid.UUID, anonymise bool) ([]uuid.UUID, error) {
+	exportIds, err := repo.next.Erase(Id, pseudonym, anonymise)
+	repo.invalidate(Id)
+	return exportIds, err
+}
+
+func (repo *CachedUserRepository) load(Id uuid.UUID) (*repointerfaces.AccountState, bool) {
+	ctx, cancel := context.WithTimeout(repo.ctx, cacheOpTimeout)
+	defer cancel()
+
+	data, err := repo.rdb.Get(ctx, accountCacheKey(Id)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			slog.WarnContext(repo.ctx, "Account cache read failed, falling back to database", "error", err)
+		}
+		return nil, false
+	}
+
+	var state repointerfaces.AccountState
+	if err := json.Unmarshal(data, &state); err != nil {
+		slog.WarnContext(repo.ctx, "Discarding unreadable cached account state", "user_id", Id, "error", err)
+		repo.invalidate(Id)
+		return nil, false
+	}
+	return &state, true
+}
+
+func (repo *CachedUserRepository) store(Id uuid.UUID, state *repointerfaces.AccountState) {
+	ctx, cancel := context.WithTimeout(repo.ctx, cacheOpTimeout)
+	defer cancel()
+
+	data, err := json.Marshal(state)
+	if err == nil {
+		err = repo.rdb.Set(ctx, accountCacheKey(Id), data, repo.ttl).Err()
+	}
+	if err != nil {
+		slog.WarnContext(repo.ctx, "Account cache write failed", "user_id", Id, "error", err)
+	}
+}
+
+// invalidate drops the cached state of the account, retrying like a session
+// revocation since a stale entry can let a suspended account back in.
+func (repo *CachedUserRepository) invalidate(Id uuid.UUID) {
+	var err error
+	for attempt := 1; attempt <= revocationAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(repo.ctx, cacheOpTimeout)
+		err = repo.rdb.Del(ctx, accountCacheKey(Id)).Err()
+		cancel()
+		if err == nil {
+			return
+		}
+		if attempt < revocationAttempts {
+			time.Sleep(time.Duration(attempt) * revocationRetryDelay)
+		}
+	}
+	slog.ErrorContext(repo.ctx, "Account cache invalidation failed, a cached status may be used until it expires",
+		"user_id", Id, "cache_ttl", repo.ttl, "error", err)
+}
+
+func accountCacheKey(Id uuid.UUID) string {
+	return accountCachePrefix + Id.String()
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CommunityInvitationRepo struct {
+	db *gorm.DB
+}
+
+func NewCommunityInvitationRepo(db *gorm.DB) repointerfaces.CommunityInvitationRepoInterface {
+	return &CommunityInvitationRepo{db: db}
+}
+
+func (repo *CommunityInvitationRepo) WithContext(ctx context.Context) repointerfaces.CommunityInvitationRepoInterface {
+	return &CommunityInvitationRepo{db: repo.db.WithContext(ctx)}
+}
+
+func (repo *CommunityInvitationRepo) Create(invitation *entity.CommunityInvitation) error {
+	return repo.db.Create(invitation).Error
+}
+
+func (repo *CommunityInvitationRepo) GetById(Id uuid.UUID) (*entity.CommunityInvitation, error) {
+	var invitation entity.CommunityInvitation
+	if err := repo.db.Where("id = ?", Id).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (repo *CommunityInvitationRepo) FindPending(communityId uuid.UUID, inviteeId uuid.UUID, now time.Time) (*entity.CommunityInvitation, error) {
+	var invitation entity.CommunityInvitation
+	err := pendingInvitations(repo.db, now).
+		Where("community_id = ? AND invitee_id = ?", communityId, inviteeId).
+		First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (repo *CommunityInvitationRepo) ListPendingForCommunity(communityId uuid.UUID, now time.Time) ([]*entity.CommunityInvitation, error) {
+	var invitations []*entity.CommunityInvitation
+	err := pendingInvitations(repo.db, now).Preload("Invitee").
+		Where("community_id = ?", communityId).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (repo *CommunityInvitationRepo) ListPendingForUser(userId uuid.UUID, now time.Time) ([]*entity.CommunityInvitation, error) {
+	var invitations []*entity.CommunityInvitation
+	err := pendingInvitations(repo.db, now).Preload("Community").
+		Where("invitee_id = ?", userId).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (repo *CommunityInvitationRepo) Accept(Id uuid.UUID, membership *entity.CommunityMembership) (bool, error) {
+	accepted := false
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.CommunityInvitation{}).
+			Where("id = ? AND status = ?", Id, entity.InvitationStatusPending).
+			Updates(map[string]interface{}{"status": entity.InvitationStatusAccepted, "responded_at": time.Now().UTC()})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		accepted = true
+		return addMembership(tx, membership)
+	})
+	return accepted, err
+}
+
+func (repo *CommunityInvitationRepo) Close(Id uuid.UUID, status string) (bool, error) {
+	result := repo.db.Model(&entity.CommunityInvitation{}).
+		Where("id = ? AND status = ?", Id, entity.InvitationStatusPending).
+		Updates(map[string]interface{}{"status": status, "responded_at": time.Now().UTC()})
+	return result.RowsAffected > 0, result.Error
+}
+
+func pendingInvitations(db *gorm.DB, now time.Time) *gorm.DB {
+	return db.Where("status = ? AND expires_at > ?", entity.InvitationStatusPending, now)
+}
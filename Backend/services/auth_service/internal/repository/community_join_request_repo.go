@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CommunityJoinRequestRepo struct {
+	db *gorm.DB
+}
+
+func NewCommunityJoinRequestRepo(db *gorm.DB) repointerfaces.CommunityJoinRequestRepoInterface {
+	return &CommunityJoinRequestRepo{db: db}
+}
+
+func (repo *CommunityJoinRequestRepo) WithContext(ctx context.Context) repointerfaces.CommunityJoinRequestRepoInterface {
+	return &CommunityJoinRequestRepo{db: repo.db.WithContext(ctx)}
+}
+
+func (repo *CommunityJoinRequestRepo) Create(request *entity.CommunityJoinRequest) error {
+	return repo.db.Create(request).Error
+}
+
+func (repo *CommunityJoinRequestRepo) GetById(Id uuid.UUID) (*entity.CommunityJoinRequest, error) {
+	var request entity.CommunityJoinRequest
+	if err := repo.db.Where("id = ?", Id).First(&request).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (repo *CommunityJoinRequestRepo) FindPending(communityId uuid.UUID, userId uuid.UUID) (*entity.CommunityJoinRequest, error) {
+	var request entity.CommunityJoinRequest
+	err := repo.db.Where("community_id = ? AND user_id = ? AND status = ?", communityId, userId, entity.JoinRequestStatusPending).
+		First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (repo *CommunityJoinRequestRepo) ListPending(communityId uuid.UUID) ([]*entity.CommunityJoinRequest, error) {
+	var requests []*entity.CommunityJoinRequest
+	err := repo.db.Preload("User").
+		Where("community_id = ? AND status = ?", communityId, entity.JoinRequestStatusPending).
+		Order("created_at").
+		Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (repo *CommunityJoinRequestRepo) Approve(Id uuid.UUID, decidedBy uuid.UUID, membership *entity.CommunityMembership) (bool, error) {
+	approved := false
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		decided, err := decideJoinRequest(tx, Id, decidedBy, entity.JoinRequestStatusApproved)
+		if err != nil || !decided {
+			return err
+		}
+		approved = true
+		return addMembership(tx, membership)
+	})
+	return approved, err
+}
+
+func (repo *CommunityJoinRequestRepo) Reject(Id uuid.UUID, decidedBy uuid.UUID) (bool, error) {
+	return decideJoinRequest(repo.db, Id, decidedBy, entity.JoinRequestStatusRejected)
+}
+
+func decideJoinRequest(db *gorm.DB, Id uuid.UUID, decidedBy uuid.UUID, status string) (bool, error) {
+	result := db.Model(&entity.CommunityJoinRequest{}).
+		Where("id = ? AND status = ?", Id, entity.JoinRequestStatusPending).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"decided_by": decidedBy,
+			"decided_at": time.Now().UTC(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
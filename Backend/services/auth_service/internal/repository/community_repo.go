@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CommunityRepo struct {
+	db *gorm.DB
+}
+
+func NewCommunityRepo(db *gorm.DB) repointerfaces.CommunityRepoInterface {
+	return &CommunityRepo{db: db}
+}
+
+func (repo *CommunityRepo) WithContext(ctx context.Context) repointerfaces.CommunityRepoInterface {
+	return &CommunityRepo{db: repo.db.WithContext(ctx)}
+}
+
+func (repo *CommunityRepo) Create(community *entity.Community, ownerId uuid.UUID) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(community).Error; err != nil {
+			return err
+		}
+		return tx.Create(&entity.CommunityMembership{
+			CommunityID: community.ID,
+			UserID:      ownerId,
+			Role:        entity.CommunityRoleOwner,
+		}).Error
+	})
+}
+
+func (repo *CommunityRepo) GetById(Id uuid.UUID) (*entity.Community, error) {
+	var community entity.Community
+	if err := repo.db.Where("id = ?", Id).First(&community).Error; err != nil {
+		return nil, err
+	}
+	return &community, nil
+}
+
+func (repo *CommunityRepo) GetBySlug(slug string) (*entity.Community, error) {
+	var community entity.Community
+	if err := repo.db.Where("slug = ?", slug).First(&community).Error; err != nil {
+		return nil, err
+	}
+	return &community, nil
+}
+
+// Delete removes the community; memberships, invitations and join requests
+// go with it.
+func (repo *CommunityRepo) Delete(Id uuid.UUID) error {
+	result := repo.db.Where("id = ?", Id).Delete(&entity.Community{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (repo *CommunityRepo) ListForUser(userId uuid.UUID) ([]*entity.CommunityMembership, error) {
+	var memberships []*entity.CommunityMembership
+	err := repo.db.Preload("Community").
+		Where("user_id = ?", userId).
+		Order("created_at").
+		Find(&memberships).Error
+	if err != nil {
+		return nil, err
+	}
+	return memberships, nil
+}
+
+func (repo *CommunityRepo) GetMembership(communityId uuid.UUID, userId uuid.UUID) (*entity.CommunityMembership, error) {
+	var membership entity.CommunityMembership
+	err := repo.db.Where("community_id = ? AND user_id = ?", communityId, userId).First(&membership).Error
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+func (repo *CommunityRepo) ListMembers(communityId uuid.UUID, offset int, limit int) ([]*entity.CommunityMembership, int64, error) {
+	query := repo.db.Model(&entity.CommunityMembership{}).Where("community_id = ?", communityId)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var memberships []*entity.CommunityMembership
+	err := query.Preload("User").
+		Order("created_at").Order("user_id").
+		Offset(offset).Limit(limit).
+		Find(&memberships).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return memberships, total, nil
+}
+
+func (repo *CommunityRepo) UpdateMemberRole(communityId uuid.UUID, userId uuid.UUID, role string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if role != entity.CommunityRoleOwner {
+			if err := keepOwner(tx, communityId, userId); err != nil {
+				return err
+			}
+		}
+		result := tx.Model(&entity.CommunityMembership{}).
+			Where("community_id = ? AND user_id = ?", communityId, userId).
+			Update("role", role)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (repo *CommunityRepo) RemoveMember(communityId uuid.UUID, userId uuid.UUID) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := keepOwner(tx, communityId, userId); err != nil {
+			return err
+		}
+		result := tx.Where("community_id = ? AND user_id = ?", communityId, userId).Delete(&entity.CommunityMembership{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// lockOwners locks the owner memberships of the community until the end of
+// tx and returns the owners' IDs. Changes that could leave a community without
+// an owner take this lock first, so they cannot interleave.
+func lockOwners(tx *gorm.DB, communityId uuid.UUID) ([]uuid.UUID, error) {
+	var owners []uuid.UUID
+	err := tx.Model(&entity.CommunityMembership{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("community_id = ? AND role = ?", communityId, entity.CommunityRoleOwner).
+		Order("user_id").
+		Pluck("user_id", &owners).Error
+	return owners, err
+}
+
+// keepOwner returns ErrLastOwner when userId is the community's only owner,
+// so they may neither leave nor step down.
+func keepOwner(tx *gorm.DB, communityId uuid.UUID, userId uuid.UUID) error {
+	owners, err := lockOwners(tx, communityId)
+	if err != nil {
+		return err
+	}
+	if len(owners) == 1 && owners[0] == userId {
+		return repointerfaces.ErrLastOwner
+	}
+	return nil
+}
+
+// handOver keeps the community owned once leaving is gone. If leaving is its
+// only owner, the longest-standing moderator, or else member, becomes owner;
+// a community with nobody left to take over is deleted.
+func handOver(tx *gorm.DB, communityId uuid.UUID, leaving uuid.UUID) error {
+	owners, err := lockOwners(tx, communityId)
+	if err != nil {
+		return err
+	}
+	for _, owner := range owners {
+		if owner != leaving {
+			return nil
+		}
+	}
+
+	var successors []*entity.CommunityMembership
+	err = tx.Where("community_id = ? AND user_id <> ?", communityId, leaving).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "role = ? DESC, created_at, user_id",
+			Vars: []interface{}{entity.CommunityRoleModerator},
+		}}).
+		Limit(1).
+		Find(&successors).Error
+	if err != nil {
+		return err
+	}
+	if len(successors) == 0 {
+		return tx.Where("id = ?", communityId).Delete(&entity.Community{}).Error
+	}
+	return tx.Model(&entity.CommunityMembership{}).
+		Where("community_id = ? AND user_id = ?", communityId, successors[0].UserID).
+		Update("role", entity.CommunityRoleOwner).Error
+}
+
+// addMembership inserts membership unless the user already belongs to the
+// community, in which case their current role is kept.
+func addMembership(tx *gorm.DB, membership *entity.CommunityMembership) error {
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(membership).Error
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DataExportRepo struct {
+	db *gorm.DB
+}
+
+func NewDataExportRepo(db *gorm.DB) repointerfaces.DataExportRepoInterface {
+	return &DataExportRepo{db: db}
+}
+
+func (repo *DataExportRepo) WithContext(ctx context.Context) repointerfaces.DataExportRepoInterface {
+	return &DataExportRepo{db: repo.db.WithContext(ctx)}
+}
+
+// inProgressExport is the predicate of idx_data_exports_user_in_progress.
+// It is spelled out rather than bound so Postgres can match the index to it.
+var inProgressExport = clause.Expr{SQL: "status IN ('" + entity.ExportStatusPending + "', '" + entity.ExportStatusProcessing + "')"}
+
+// Create queues export unless the user already has one in progress, which
+// the partial unique index on user_id decides atomically.
+func (repo *DataExportRepo) Create(export *entity.DataExport) error {
+	result := repo.db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "user_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{inProgressExport}},
+		DoNothing:   true,
+	}).Create(export)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repointerfaces.ErrExportInProgress
+	}
+	return nil
+}
+
+func (repo *DataExportRepo) GetById(Id uuid.UUID) (*entity.DataExport, error) {
+	var export entity.DataExport
+	if err := repo.db.Where("id = ?", Id).First(&export).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (repo *DataExportRepo) ClaimNext(staleBefore time.Time) (*entity.DataExport, error) {
+	var claimed *entity.DataExport
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		// Find rather than First: an empty queue is the normal case and not worth logging.
+		var exports []*entity.DataExport
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND updated_at < ?)", entity.ExportStatusPending, entity.ExportStatusProcessing, staleBefore).
+			Order("created_at").
+			Limit(1).
+			Find(&exports).Error
+		if err != nil || len(exports) == 0 {
+			return err
+		}
+		claimed = exports[0]
+		claimed.Status = entity.ExportStatusProcessing
+		return tx.Model(claimed).Updates(map[string]interface{}{"status": entity.ExportStatusProcessing}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+func (repo *DataExportRepo) Requeue(Id uuid.UUID) error {
+	return repo.db.Model(&entity.DataExport{}).
+		Where("id = ? AND status = ?", Id, entity.ExportStatusProcessing).
+		Update("status", entity.ExportStatusPending).Error
+}
+
+func (repo *DataExportRepo) MarkReady(Id uuid.UUID, completedAt time.Time, expiresAt time.Time) error {
+	return repo.db.Model(&entity.DataExport{}).
+		Where("id = ?", Id).
+		Updates(map[string]interface{}{
+			"status":       entity.ExportStatusReady,
+			"completed_at": completedAt,
+			"expires_at":   expiresAt,
+		}).Error
+}
+
+func (repo *DataExportRepo) MarkFailed(Id uuid.UUID, reason string, expiresAt time.Time) error {
+	return repo.db.Model(&entity.DataExport{}).
+		Where("id = ?", Id).
+		Updates(map[string]interface{}{"status": entity.ExportStatusFailed, "error": reason, "expires_at": expiresAt}).Error
+}
+
+func (repo *DataExportRepo) DeleteExpired(now time.Time) ([]uuid.UUID, error) {
+	var expired []entity.DataExport
+	err := repo.db.Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("expires_at <= ?", now).
+		Delete(&expired).Error
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(expired))
+	for _, export := range expired {
+		ids = append(ids, export.ID)
+	}
+	return ids, nil
+}
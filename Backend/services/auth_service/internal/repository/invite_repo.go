@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InviteRepo struct {
+	db *gorm.DB
+}
+
+func NewInviteRepo(db *gorm.DB) repointerfaces.InviteRepoInterface {
+	return &InviteRepo{db: db}
+}
+
+func (repo *InviteRepo) WithContext(ctx context.Context) repointerfaces.InviteRepoInterface {
+	return &InviteRepo{db: repo.db.WithContext(ctx)}
+}
+
+func (repo *InviteRepo) Create(invite *entity.InviteCode) error {
+	return repo.db.Create(invite).Error
+}
+
+func (repo *InviteRepo) GetById(Id uuid.UUID) (*entity.InviteCode, error) {
+	var invite entity.InviteCode
+	if err := repo.db.Where("id = ?", Id).First(&invite).Error; err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (repo *InviteRepo) List(filter repointerfaces.InviteFilter) ([]*entity.InviteCode, int64, error) {
+	query := repo.db.Model(&entity.InviteCode{})
+	if filter.CreatedBy != nil {
+		query = query.Where("created_by = ?", *filter.CreatedBy)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var invites []*entity.InviteCode
+	err := query.Preload("Redemptions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("redeemed_at")
+	}).Preload("Redemptions.User").
+		Order("created_at DESC, id").
+		Offset(filter.Offset).Limit(filter.Limit).
+		Find(&invites).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return invites, total, nil
+}
+
+func (repo *InviteRepo) CountActive(createdBy uuid.UUID, now time.Time) (int64, error) {
+	var count int64
+	err := repo.db.Model(&entity.InviteCode{}).
+		Where("created_by = ? AND revoked_at IS NULL", createdBy).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Where("max_uses = 0 OR uses < max_uses").
+		Count(&count).Error
+	return count, err
+}
+
+func (repo *InviteRepo) Revoke(Id uuid.UUID) (bool, error) {
+	result := repo.db.Model(&entity.InviteCode{}).
+		Where("id = ? AND revoked_at IS NULL", Id).
+		Update("revoked_at", time.Now().UTC())
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PersonalTokenRepo struct {
+	db *gorm.DB
+}
+
+func NewPersonalTokenRepo(db *gorm.DB) repointerfaces.PersonalTokenRepoInterface {
+	return &PersonalTokenRepo{db: db}
+}
+
+func (repo *PersonalTokenRepo) WithContext(ctx context.Context) repointerfaces.PersonalTokenRepoInterface {
+	return &PersonalTokenRepo{db: repo.db.WithContext(ctx)}
+}
+
+func (repo *PersonalTokenRepo) Create(token *entity.PersonalAccessToken) error {
+	return repo.db.Create(token).Error
+}
+
+func (repo *PersonalTokenRepo) GetByHash(tokenHash string) (*entity.PersonalAccessToken, error) {
+	var token entity.PersonalAccessToken
+	if err := repo.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (repo *PersonalTokenRepo) ListActive(userId uuid.UUID, now time.Time) ([]*entity.PersonalAccessToken, error) {
+	var tokens []*entity.PersonalAccessToken
+	err := activeTokens(repo.db, userId, now).Order("created_at DESC").Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (repo *PersonalTokenRepo) CountActive(userId uuid.UUID, now time.Time) (int64, error) {
+	var count int64
+	err := activeTokens(repo.db.Model(&entity.PersonalAccessToken{}), userId, now).Count(&count).Error
+	return count, err
+}
+
+func (repo *PersonalTokenRepo) Revoke(Id uuid.UUID, userId uuid.UUID) (bool, error) {
+	result := repo.db.Model(&entity.PersonalAccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", Id, userId).
+		Update("revoked_at", time.Now().UTC())
+	return result.RowsAffected > 0, result.Error
+}
+
+func (repo *PersonalTokenRepo) RevokeAllForUser(userId uuid.UUID) error {
+	return repo.db.Model(&entity.PersonalAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userId).
+		Update("revoked_at", time.Now().UTC()).Error
+}
+
+func (repo *PersonalTokenRepo) UpdateLastUsed(Id uuid.UUID, at time.Time) error {
+	return repo.db.Model(&entity.PersonalAccessToken{}).Where("id = ?", Id).Update("last_used_at", at).Error
+}
+
+func activeTokens(db *gorm.DB, userId uuid.UUID, now time.Time) *gorm.DB {
+	return db.Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userId, now)
+}
@@ -3,19 +3,30 @@ package repository
 import (
 	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
 	"auth/internal/domain/entity"
-	"fmt"
+	"auth/internal/infrastructure/metrics"
+	"auth/pkg/revocation"
+	"context"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SessionRepository struct {
 	db *gorm.DB
+	publisher *revocation.Publisher
 }
 
-func NewSessionRepository(db *gorm.DB) repointerfaces.SessionRepoInterface {
-	return &SessionRepository{db: db}
+// NewSessionRepository returns a Postgres-backed session repository. When
+// publisher is not nil, every revocation is broadcast to other services.
+func NewSessionRepository(db *gorm.DB, publisher *revocation.Publisher) repointerfaces.SessionRepoInterface {
+	return &SessionRepository{db: db, publisher: publisher}
+}
+
+func (repo *SessionRepository) WithContext(ctx context.Context) repointerfaces.SessionRepoInterface {
+	return &SessionRepository{db: repo.db.WithContext(ctx), publisher: repo.publisher}
 }
 
 func (repo *SessionRepository) AddSession(session *entity.Session) (*entity.Session, error) {
@@ -25,10 +36,61 @@ func (repo *SessionRepository) AddSession(session *entity.Session) (*entity.Sess
 	}
 	return session, nil
 }
+// AddSessionWithLimit stores session unless the user already holds limit.Max
+// active sessions, in which case it either fails with ErrSessionLimitReached
+// or revokes the least recently used sessions to make room. The user row is
+// locked for the duration so concurrent logins are counted one at a time.
+// It returns the IDs of evicted sessions.
+func (repo *SessionRepository) AddSessionWithLimit(session *entity.Session, limit repointerfaces.SessionLimit) ([]uuid.UUID, error) {
+	now := time.Now()
+	var evicted []entity.Session
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", session.UserID).First(&user).Error
+		if err != nil {
+			return err
+		}
+
+		var active []entity.Session
+		query := tx.Select("id").
+			Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", session.UserID, now.UTC())
+		if !limit.IdleSince.IsZero() {
+			query = query.Where("last_used_at >= ?", limit.IdleSince)
+		}
+		if err := query.Order("last_used_at ASC").Find(&active).Error; err != nil {
+			return err
+		}
+
+		if excess := len(active) + 1 - limit.Max; excess > 0 {
+			if !limit.EvictLRU {
+				return repointerfaces.ErrSessionLimitReached
+			}
+			ids := make([]uuid.UUID, 0, excess)
+			for _, s := range active[:excess] {
+				ids = append(ids, s.ID)
+			}
+			evicted, err = revokeMatching(tx.Where("id IN ?", ids), now, entity.RevokeReasonSessionLimit)
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(session).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.SessionsRevoked.WithLabelValues(entity.RevokeReasonSessionLimit).Add(float64(len(evicted)))
+	repo.publishRevoked(evicted, now)
+	ids := make([]uuid.UUID, 0, len(evicted))
+	for _, s := range evicted {
+		ids = append(ids, s.ID)
+	}
+	return ids, nil
+}
 func (repo *SessionRepository) GetAll(userId uuid.UUID) ([]*entity.Session, error) {
 	var sessions []*entity.Session
-	now := time.Now().UTC()
-	fmt.Println("Now is:", now)
 	err := repo.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userId, time.Now().UTC()).Order("last_used_at DESC").Find(&sessions).Error
 	if err != nil {
 		return nil, err
@@ -36,6 +98,78 @@ func (repo *SessionRepository) GetAll(userId uuid.UUID) ([]*entity.Session, erro
 
 	return sessions, nil
 }
+// ListArchived returns the user's sessions moved to the archive by the janitor, newest first.
+func (repo *SessionRepository) ListArchived(userId uuid.UUID) ([]*entity.SessionArchive, error) {
+	var sessions []*entity.SessionArchive
+	err := repo.db.Where("user_id = ?", userId).Order("created_at DESC, id DESC").Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+// ListHistory returns the user's sessions, including revoked, expired and
+// archived ones, newest first.
+func (repo *SessionRepository) ListHistory(userId uuid.UUID, filter repointerfaces.SessionHistoryFilter) ([]*entity.Session, error) {
+	var sessions []*entity.Session
+	query := historyScope(repo.db, userId, filter)
+	if filter.AfterID != nil {
+		query = query.Where("(created_at, id) < (?, ?)", filter.AfterCreatedAt, *filter.AfterID)
+	}
+	err := query.Order("created_at DESC, id DESC").Limit(filter.Limit).Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// SummarizeHistory aggregates the user's sessions, archived ones included,
+// within the filter's date range by device and by IP, most recently seen first.
+func (repo *SessionRepository) SummarizeHistory(userId uuid.UUID, filter repointerfaces.SessionHistoryFilter) ([]repointerfaces.DeviceUsage, []repointerfaces.IPUsage, error) {
+	var devices []repointerfaces.DeviceUsage
+	err := historyScope(repo.db, userId, filter).
+		Select("browser, os, device_type, device_name, COUNT(*) AS sessions, MAX(created_at) AS last_seen").
+		Group("browser, os, device_type, device_name").
+		Order("last_seen DESC").
+		Scan(&devices).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ips []repointerfaces.IPUsage
+	err = historyScope(repo.db, userId, filter).
+		Select("ip, COUNT(*) AS sessions, MAX(created_at) AS last_seen").
+		Group("ip").
+		Order("last_seen DESC").
+		Scan(&ips).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return devices, ips, nil
+}
+
+// historyColumns are the session columns kept in session_archives.
+const historyColumns = "id, user_id, expires_at, user_agent, ip, browser, os, device_type, device_name, last_used_at, created_at, revoked_at, revoked_reason"
+
+// historyScope selects the user's sessions created in the filter's date range
+// from both sessions and session_archives, so history does not lose sessions
+// once the janitor archives them, unless the filter excludes archived ones.
+func historyScope(db *gorm.DB, userId uuid.UUID, filter repointerfaces.SessionHistoryFilter) *gorm.DB {
+	live := db.Model(&entity.Session{}).Select(historyColumns).Where("user_id = ?", userId)
+	source := db.Raw("?", live)
+	if !filter.ExcludeArchived {
+		archived := db.Model(&entity.SessionArchive{}).Select(historyColumns).Where("user_id = ?", userId)
+		source = db.Raw("? UNION ALL ?", live, archived)
+	}
+	query := db.Table("(?) AS sessions", source)
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	return query
+}
+
 func (repo *SessionRepository) GetById(Id uuid.UUID) (*entity.Session, error) {
 	var session entity.Session
 	err := repo.db.Where("id = ?", Id).First(&session, Id).Error
@@ -44,33 +178,131 @@ func (repo *SessionRepository) GetById(Id uuid.UUID) (*entity.Session, error) {
 	}
 	return &session, nil
 }
-func (repo *SessionRepository) RevokeSession(Id uuid.UUID) error {
+func (repo *SessionRepository) GetWithTokenHash(Id uuid.UUID) (*entity.Session, error) {
+	return repo.GetById(Id)
+}
+func (repo *SessionRepository) RevokeSession(Id uuid.UUID, reason string) error {
+	return repo.revoke(repo.db.Where("id = ?", Id), reason)
+}
+func (repo *SessionRepository) RevokeForAllUser(userId uuid.UUID, reason string) error {
+	return repo.revoke(repo.db.Where("user_id = ?", userId), reason)
+}
+func (repo *SessionRepository) RevokeAllExceptCurrent(userId uuid.UUID, keepSessionId uuid.UUID, reason string) error {
+	return repo.revoke(repo.db.Where("user_id = ? AND id != ?", userId, keepSessionId), reason)
+}
+
+// revoke marks the not yet revoked sessions matched by scope as revoked and
+// publishes a revocation event for each of them.
+func (repo *SessionRepository) revoke(scope *gorm.DB, reason string) error {
 	now := time.Now()
-	err := repo.db.Model(&entity.Session{}).Where("id = ?", Id).Update("revoked_at", &now).Error
+	revoked, err := revokeMatching(scope, now, reason)
 	if err != nil {
 		return err
 	}
+
+	metrics.SessionsRevoked.WithLabelValues(reason).Add(float64(len(revoked)))
+	repo.publishRevoked(revoked, now)
 	return nil
 }
-func (repo *SessionRepository) RevokeForAllUser(userId uuid.UUID) error {
-	now := time.Now()
-	return repo.db.Model(&entity.Session{}).
-		Where("user_id = ?", userId).
-		Update("revoked_at", &now).Error
+
+// revokeMatching sets revoked_at and the reason on the not yet revoked
+// sessions matched by scope and returns their IDs and owners.
+func revokeMatching(scope *gorm.DB, now time.Time, reason string) ([]entity.Session, error) {
+	var revoked []entity.Session
+	err := scope.Model(&revoked).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "user_id"}}}).
+		Where("revoked_at IS NULL").
+		Updates(map[string]interface{}{"revoked_at": &now, "revoked_reason": reason}).Error
+	return revoked, err
 }
-func (repo *SessionRepository) RevokeAllExceptCurrent(userId uuid.UUID, keepSessionId uuid.UUID) error {
-	now := time.Now()
-	err := repo.db.Model(&entity.Session{}).
-		Where("user_id = ? AND id != ?", userId, keepSessionId).
-		Update("revoked_at", &now).Error
 
-	if err != nil {
-		return err
+// publishRevoked broadcasts revocations. Failures are only logged: the
+// sessions are already revoked here, and subscribers fall back to the short
+// access token lifetime.
+func (repo *SessionRepository) publishRevoked(sessions []entity.Session, revokedAt time.Time) {
+	if repo.publisher == nil || len(sessions) == 0 {
+		return
+	}
+	events := make([]revocation.Event, 0, len(sessions))
+	for _, session := range sessions {
+		events = append(events, revocation.Event{SessionID: session.ID, UserID: session.UserID, RevokedAt: revokedAt.UTC()})
+	}
+
+	// Revocations are published even when the request was cancelled meanwhile
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(repo.db.Statement.Context), 2*time.Second)
+	defer cancel()
+	if err := repo.publisher.Publish(ctx, events...); err != nil {
+		slog.ErrorContext(ctx, "Failed to broadcast session revocation", "error", err)
 	}
-	return nil
 }
 func (repo *SessionRepository) UpdateLastUsed(Id uuid.UUID) error {
 	return repo.db.Model(&entity.Session{}).
 		Where("id = ?", Id).
 		Update("last_used_at", time.Now()).Error
 }
+
+// UpdateDevice stores the client metadata (IP, user agent and the parsed device
+// fields) carried by session. The device name is only replaced when one is given.
+func (repo *SessionRepository) UpdateDevice(session *entity.Session) error {
+	columns := []string{"ip", "user_agent", "browser", "os", "device_type"}
+	if session.DeviceName != "" {
+		columns = append(columns, "device_name")
+	}
+	return repo.db.Model(&entity.Session{}).
+		Where("id = ?", session.ID).
+		Select(columns).
+		Updates(session).Error
+}
+
+// Renew rotates the session's refresh token hash from oldTokenHash to
+// tokenHash and moves its expiry, marking the session as used now. It returns
+// ErrRefreshTokenMismatch when the session no longer holds oldTokenHash, or
+// was revoked or purged in the meantime, so only one of several concurrent
+// refreshes with the same token can succeed.
+func (repo *SessionRepository) Renew(Id uuid.UUID, oldTokenHash string, tokenHash string, expiresAt time.Time) error {
+	result := repo.db.Model(&entity.Session{}).
+		Where("id = ? AND token_hash = ? AND revoked_at IS NULL", Id, oldTokenHash).
+		Updates(map[string]interface{}{
+			"token_hash":   tokenHash,
+			"expires_at":   expiresAt,
+			"last_used_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repointerfaces.ErrRefreshTokenMismatch
+	}
+	return nil
+}
+
+func (repo *SessionRepository) SetActiveCommunity(Id uuid.UUID, communityId *uuid.UUID) error {
+	return repo.db.Model(&entity.Session{}).
+		Where("id = ? AND revoked_at IS NULL", Id).
+		Update("active_community_id", communityId).Error
+}
+
+// purgeableSessions selects up to limit sessions that were revoked or expired before the cutoff.
+const purgeableSessions = `SELECT id FROM sessions WHERE revoked_at < @before OR expires_at < @before ORDER BY expires_at LIMIT @limit`
+
+// PurgeInactive removes up to limit sessions that were revoked or expired
+// before the cutoff, copying them into session_archives first when archive is
+// set. It returns the number of rows removed.
+func (repo *SessionRepository) PurgeInactive(before time.Time, archive bool, limit int) (int64, error) {
+	args := map[string]interface{}{"before": before, "limit": limit}
+	if !archive {
+		res := repo.db.Exec(`DELETE FROM sessions WHERE id IN (`+purgeableSessions+`)`, args)
+		return res.RowsAffected, res.Error
+	}
+
+	res := repo.db.Exec(`
+		WITH moved AS (
+			DELETE FROM sessions WHERE id IN (`+purgeableSessions+`)
+			RETURNING id, user_id, expires_at, user_agent, ip, browser, os, device_type, device_name, last_used_at, created_at, revoked_at, revoked_reason
+		)
+		INSERT INTO session_archives (id, user_id, expires_at, user_agent, ip, browser, os, device_type, device_name, last_used_at, created_at, revoked_at, revoked_reason, archived_at)
+		SELECT id, user_id, expires_at, user_agent, ip, browser, os, device_type, device_name, last_used_at, created_at, revoked_at, revoked_reason, NOW()
+		FROM moved
+		ON CONFLICT (id) DO NOTHING`, args)
+	return res.RowsAffected, res.Error
+}
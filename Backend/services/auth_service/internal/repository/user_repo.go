@@ -1,11 +1,16 @@
 package repository
 
 import (
+	"context"
+	"strings"
+	"time"
+
 	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
 	"auth/internal/domain/entity"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepo struct {
@@ -16,6 +21,10 @@ func NewUserRepo(db *gorm.DB) repointerfaces.UserRepoInterface{
 	return &UserRepo{db:db}
 }
 
+func (repo *UserRepo) WithContext(ctx context.Context) repointerfaces.UserRepoInterface {
+	return &UserRepo{db: repo.db.WithContext(ctx)}
+}
+
 func (repo *UserRepo) Create(user *entity.User) (*entity.User, error){
 	err := repo.db.Create(user).Error
 	if err != nil{
@@ -23,6 +32,40 @@ func (repo *UserRepo) Create(user *entity.User) (*entity.User, error){
 	}
 	return user, nil
 }
+func (repo *UserRepo) CreateWithInvite(user *entity.User, code string, now time.Time) (*entity.User, error) {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var invite entity.InviteCode
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("code = ? AND revoked_at IS NULL", code).
+			Where("expires_at IS NULL OR expires_at > ?", now).
+			Where("max_uses = 0 OR uses < max_uses").
+			Limit(1).Find(&invite)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repointerfaces.ErrInviteCodeInvalid
+		}
+
+		if err := tx.Model(&entity.InviteCode{}).Where("id = ?", invite.ID).
+			Update("uses", gorm.Expr("uses + 1")).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(&entity.InviteRedemption{
+			InviteCodeID: &invite.ID,
+			UserID:       user.ID,
+			RedeemedAt:   now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
 func (repo *UserRepo) GetById(Id uuid.UUID) (*entity.User, error){
 	var user entity.User
 	err := repo.db.Where("id = ?", Id).First(&user).Error
@@ -46,4 +89,215 @@ func (repo *UserRepo) GetByUsername(username string) (*entity.User, error){
 		return nil, err
 	}
 	return &user,nil
-}
\ No newline at end of file
+}
+
+// GetAccountState loads only the columns needed to decide whether the user
+// may sign in or keep using their sessions.
+func (repo *UserRepo) GetAccountState(Id uuid.UUID) (*repointerfaces.AccountState, error) {
+	var state repointerfaces.AccountState
+	err := repo.db.Model(&entity.User{}).
+		Select("status, status_reason, status_expires_at, password_reset_required").
+		Where("id = ?", Id).
+		Take(&state).Error
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// UpdatePassword stores a new password hash and clears any pending forced reset.
+func (repo *UserRepo) UpdatePassword(Id uuid.UUID, passwordHash string) error {
+	return repo.db.Model(&entity.User{}).
+		Where("id = ?", Id).
+		Updates(map[string]interface{}{"password_hash": passwordHash, "password_reset_required": false}).Error
+}
+
+// SetPasswordResetRequired flags whether the user must reset their password before logging in again.
+func (repo *UserRepo) SetPasswordResetRequired(Id uuid.UUID, required bool) error {
+	return repo.db.Model(&entity.User{}).
+		Where("id = ?", Id).
+		Update("password_reset_required", required).Error
+}
+
+// Search returns one page of users matching filter, newest first, together
+// with the total number of matches.
+func (repo *UserRepo) Search(filter repointerfaces.UserFilter) ([]*entity.User, int64, error) {
+	query := repo.db.Model(&entity.User{})
+	if filter.Query != "" {
+		like := "%" + escapeLike(filter.Query) + "%"
+		query = query.Where("email ILIKE ? OR username ILIKE ? OR full_name ILIKE ? OR phone_number ILIKE ?", like, like, like, like)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Verified != nil {
+		query = query.Where("is_verified = ?", *filter.Verified)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []*entity.User
+	err := query.Order("created_at DESC, id").Offset(filter.Offset).Limit(filter.Limit).Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (repo *UserRepo) UpdateRole(Id uuid.UUID, role string) error {
+	return repo.db.Model(&entity.User{}).Where("id = ?", Id).Update("role", role).Error
+}
+
+func (repo *UserRepo) SetVerified(Id uuid.UUID, verified bool) error {
+	return repo.db.Model(&entity.User{}).Where("id = ?", Id).Update("is_verified", verified).Error
+}
+
+func (repo *UserRepo) SetStatus(Id uuid.UUID, status repointerfaces.AccountStatus) error {
+	return repo.db.Model(&entity.User{}).
+		Where("id = ?", Id).
+		Updates(map[string]interface{}{
+			"status":            status.Status,
+			"status_reason":     status.Reason,
+			"status_changed_by": status.ChangedBy,
+			"status_changed_at": time.Now().UTC(),
+			"status_expires_at": status.ExpiresAt,
+		}).Error
+}
+
+// LiftExpiredSuspensions reactivates every suspended account whose suspension
+// ended before now and returns how many were lifted.
+func (repo *UserRepo) LiftExpiredSuspensions(now time.Time) (int64, error) {
+	result := repo.db.Model(&entity.User{}).
+		Where("status = ? AND status_expires_at <= ?", entity.UserStatusSuspended, now).
+		Updates(map[string]interface{}{
+			"status":            entity.UserStatusActive,
+			"status_reason":     "",
+			"status_changed_by": nil,
+			"status_changed_at": now,
+			"status_expires_at": nil,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// escapeLike escapes the LIKE wildcards in s so it is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// ListDueDeletions returns accounts pending deletion whose grace period ended before now.
+func (repo *UserRepo) ListDueDeletions(now time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := repo.db.Model(&entity.User{}).
+		Where("status = ? AND status_expires_at <= ?", entity.UserStatusPendingDeletion, now).
+		Order("status_expires_at").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Erase removes the account's personal data in one transaction. Audit entries
+// and status changes made by or on the account are re-pointed at pseudonym and
+// lose their IP. The user row is then deleted, which cascades to its sessions,
+// or anonymised in place, in which case the sessions, access tokens and
+// community records are removed explicitly and the account's invite codes
+// are revoked.
+// Archived sessions and data exports are always deleted; the IDs of the
+// exports are returned so their archives can be removed. Communities the
+// account owned alone are handed over to another member, or deleted when it
+// was their last member.
+func (repo *UserRepo) Erase(Id uuid.UUID, pseudonym uuid.UUID, anonymise bool) ([]uuid.UUID, error) {
+	var exportIds []uuid.UUID
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.AuditLog{}).Where("actor_id = ?", Id).
+			Updates(map[string]interface{}{"actor_id": pseudonym, "ip": ""}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.AuditLog{}).Where("target_user_id = ?", Id).
+			Update("target_user_id", pseudonym).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.User{}).Where("status_changed_by = ?", Id).
+			Update("status_changed_by", pseudonym).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", Id).Delete(&entity.SessionArchive{}).Error; err != nil {
+			return err
+		}
+		var exports []entity.DataExport
+		if err := tx.Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+			Where("user_id = ?", Id).Delete(&exports).Error; err != nil {
+			return err
+		}
+		for _, export := range exports {
+			exportIds = append(exportIds, export.ID)
+		}
+
+		var owned []uuid.UUID
+		if err := tx.Model(&entity.CommunityMembership{}).
+			Where("user_id = ? AND role = ?", Id, entity.CommunityRoleOwner).
+			Pluck("community_id", &owned).Error; err != nil {
+			return err
+		}
+		for _, communityId := range owned {
+			if err := handOver(tx, communityId, Id); err != nil {
+				return err
+			}
+		}
+
+		if !anonymise {
+			return tx.Where("id = ?", Id).Delete(&entity.User{}).Error
+		}
+		if err := tx.Where("user_id = ?", Id).Delete(&entity.Session{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", Id).Delete(&entity.PersonalAccessToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", Id).Delete(&entity.CommunityMembership{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("invitee_id = ? OR invited_by = ?", Id, Id).Delete(&entity.CommunityInvitation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", Id).Delete(&entity.CommunityJoinRequest{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.CommunityJoinRequest{}).Where("decided_by = ?", Id).Update("decided_by", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.Community{}).Where("created_by = ?", Id).Update("created_by", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.InviteCode{}).Where("created_by = ? AND revoked_at IS NULL", Id).
+			Update("revoked_at", time.Now().UTC()).Error; err != nil {
+			return err
+		}
+		placeholder := "deleted-" + Id.String()
+		return tx.Model(&entity.User{}).Where("id = ?", Id).
+			Updates(map[string]interface{}{
+				"full_name":               "Deleted user",
+				"email":                   placeholder + "@invalid",
+				"username":                placeholder,
+				"phone_number":            placeholder,
+				"country":                 "",
+				"password_hash":           "",
+				"is_verified":             false,
+				"password_reset_required": false,
+				"status":                  entity.UserStatusDeleted,
+				"status_reason":           "",
+				"status_changed_by":       nil,
+				"status_changed_at":       time.Now().UTC(),
+				"status_expires_at":       nil,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return exportIds, nil
+}
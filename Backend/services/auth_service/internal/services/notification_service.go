@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"auth/internal/infrastructure/logging"
+
+	"github.com/google/uuid"
+)
+
+// Notification is a message for a single user. Email channels use Email,
+// Subject and Body; push channels receive the whole notification as JSON.
+type Notification struct {
+	Kind    string            `json:"kind"`
+	UserID  uuid.UUID         `json:"user_id"`
+	Email   string            `json:"-"`
+	Subject string            `json:"subject"`
+	Body    string            `json:"body"`
+	Data    map[string]string `json:"data,omitempty"`
+}
+
+// Notifier delivers notifications to users.
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// smtpNotifier sends notifications as plain-text email.
+type smtpNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPNotifier creates a Notifier that emails users through the SMTP server
+// at addr (host:port). Authentication is skipped when username is empty.
+func NewSMTPNotifier(addr, username, password, from string) Notifier {
+	var auth smtp.Auth
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &smtpNotifier{addr: addr, auth: auth, from: from}
+}
+
+func (n *smtpNotifier) Notify(ctx context.Context, notification Notification) error {
+	if notification.Email == "" {
+		return errors.New("notification has no email recipient")
+	}
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", notification.Email)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", notification.Subject)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(notification.Body, "\n", "\r\n"))
+
+	// net/smtp has no context support; run it aside so callers are not held past their deadline.
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(n.addr, n.auth, n.from, []string{notification.Email}, []byte(msg.String()))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// webhookNotifier posts notifications as JSON to a push gateway.
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a Notifier that POSTs each notification as JSON
+// to url, typically a push notification gateway. The ID of the request that
+// caused a notification is sent along as X-Request-ID.
+func NewWebhookNotifier(url string) Notifier {
+	return &webhookNotifier{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if id := logging.RequestID(ctx); id != "" {
+		req.Header.Set("X-Request-ID", id)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("push gateway responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// logNotifier only logs notifications; used when no channel is configured.
+type logNotifier struct{}
+
+func NewLogNotifier() Notifier {
+	return logNotifier{}
+}
+
+func (logNotifier) Notify(ctx context.Context, notification Notification) error {
+	slog.InfoContext(ctx, "Notification", "kind", notification.Kind, "user_id", notification.UserID, "subject", notification.Subject, "body", notification.Body)
+	return nil
+}
+
+// multiNotifier fans a notification out to several channels.
+type multiNotifier []Notifier
+
+// NewMultiNotifier sends every notification through all of notifiers and
+// reports the errors of the ones that failed.
+func NewMultiNotifier(notifiers ...Notifier) Notifier {
+	return multiNotifier(notifiers)
+}
+
+func (m multiNotifier) Notify(ctx context.Context, notification Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -9,49 +9,82 @@ import (
 )
 
 type AccessTokenClaims struct {
-	UserID    uuid.UUID `json:"uid"`
-	SessionID uuid.UUID `json:"sid"`
-	Roles     []string  `json:"roles,omitempty"`
+	UserID    uuid.UUID       `json:"uid"`
+	SessionID uuid.UUID       `json:"sid"`
+	Roles     []string        `json:"roles,omitempty"`
+	Community *CommunityClaim `json:"community,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// CommunityClaim is the community a session is acting in, and the user's
+// role there when the token was issued.
+type CommunityClaim struct {
+	ID   uuid.UUID `json:"id"`
+	Role string    `json:"role"`
+}
+
 type RefreshTokenClaims struct {
 	UserID    uuid.UUID `json:"uid"`
 	SessionID uuid.UUID `json:"sid"`
 	jwt.RegisteredClaims
 }
 
+// Purposes of action tokens.
+const (
+	PurposeDenyLogin     = "deny_login"
+	PurposePasswordReset = "password_reset"
+	PurposeDataExport    = "data_export"
+)
+
+// ActionTokenClaims authorise a single kind of action, such as the
+// "this wasn't me" link in a new-device email. Binding carries an optional
+// fingerprint of state the action must still match when it is performed.
+type ActionTokenClaims struct {
+	UserID    uuid.UUID `json:"uid"`
+	SessionID uuid.UUID `json:"sid,omitempty"`
+	Purpose   string    `json:"purpose"`
+	Binding   string    `json:"bnd,omitempty"`
+	jwt.RegisteredClaims
+}
+
 // TokenService defines the contract for token operations
 type TokenService interface {
-	GenerateAccessToken(userID, sessionID uuid.UUID, roles []string) (string, error)
+	GenerateAccessToken(userID, sessionID uuid.UUID, roles []string, community *CommunityClaim) (string, error)
 	GenerateRefreshToken(userID, sessionID uuid.UUID) (string, error)
+	GenerateActionToken(claims ActionTokenClaims, ttl time.Duration) (string, error)
 	ParseAccessToken(tokenStr string) (*AccessTokenClaims, error)
 	ParseRefreshToken(tokenStr string) (*RefreshTokenClaims, error)
+	ParseActionToken(tokenStr, purpose string) (*ActionTokenClaims, error)
 }
 
 // tokenService implements TokenService interface
 type tokenService struct {
 	accessSecret  []byte
 	refreshSecret []byte
+	actionSecret  []byte
 	accessTTL     time.Duration
 	refreshTTL    time.Duration
 }
 
-// NewTokenService creates a new instance of tokenService
-func NewTokenService(accessSecret, refreshSecret string, accessTTL, refreshTTL time.Duration) TokenService {
+// NewTokenService creates a new instance of tokenService. Action tokens are
+// signed with their own secret so they can never pass as access tokens, and
+// a leaked access secret does not let anyone forge emailed links.
+func NewTokenService(accessSecret, refreshSecret, actionSecret string, accessTTL, refreshTTL time.Duration) TokenService {
 	return &tokenService{
 		accessSecret:  []byte(accessSecret),
 		refreshSecret: []byte(refreshSecret),
+		actionSecret:  []byte(actionSecret),
 		accessTTL:     accessTTL,
 		refreshTTL:    refreshTTL,
 	}
 }
 
-func (t *tokenService) GenerateAccessToken(userID, sessionID uuid.UUID, roles []string) (string, error) {
+func (t *tokenService) GenerateAccessToken(userID, sessionID uuid.UUID, roles []string, community *CommunityClaim) (string, error) {
 	claims := AccessTokenClaims{
 		UserID:    userID,
 		SessionID: sessionID,
 		Roles:     roles,
+		Community: community,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(t.accessTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -111,3 +144,32 @@ func (t *tokenService) ParseRefreshToken(tokenStr string) (*RefreshTokenClaims,
 
 	return claims, nil
 }
+
+func (t *tokenService) GenerateActionToken(claims ActionTokenClaims, ttl time.Duration) (string, error) {
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(t.actionSecret)
+}
+
+// ParseActionToken validates tokenStr and checks that it was issued for purpose.
+func (t *tokenService) ParseActionToken(tokenStr, purpose string) (*ActionTokenClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenStr, &ActionTokenClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return t.actionSecret, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse action token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*ActionTokenClaims)
+	if !ok || !token.Valid || claims.Purpose != purpose {
+		return nil, fmt.Errorf("invalid action token claims")
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"auth/internal/delivery/http/dto"
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// authenticate looks the user up by email or username and checks password.
+func (uc *UserUsecase) authenticate(ctx context.Context, identification string, password string) (*entity.User, error) {
+	users := uc.user_repo.WithContext(ctx)
+	user, err := users.GetByEmail(identification)
+	if err != nil {
+		user, err = users.GetByUsername(identification)
+		if err != nil {
+			return nil, errors.New("user not found")
+		}
+	}
+
+	if err := comparePassword(ctx, user.PasswordHash, password); err != nil {
+		return nil, errors.New("invalid credentials")
+	}
+	return user, nil
+}
+
+// RequestDeletion schedules the account for deletion after the grace period
+// and signs it out everywhere. The password must be entered again. It returns
+// when the account will be erased.
+func (uc *UserUsecase) RequestDeletion(ctx context.Context, userID uuid.UUID, password string, client *dto.ClientInfo) (_ time.Time, err error) {
+	ctx, end := startSpan(ctx, "UserUsecase.RequestDeletion")
+	defer end(&err)
+	users := uc.user_repo.WithContext(ctx)
+	user, err := users.GetById(userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := comparePassword(ctx, user.PasswordHash, password); err != nil {
+		return time.Time{}, errors.New("invalid credentials")
+	}
+
+	deleteAt := time.Now().UTC().Add(uc.deletionGracePeriod)
+	err = users.SetStatus(user.ID, repointerfaces.AccountStatus{
+		Status:    entity.UserStatusPendingDeletion,
+		Reason:    "requested by user",
+		ChangedBy: &user.ID,
+		ExpiresAt: &deleteAt,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := signOutEverywhere(uc.session_repo.WithContext(ctx), uc.token_repo.WithContext(ctx), user.ID, entity.RevokeReasonAccountDeletion); err != nil {
+		return time.Time{}, err
+	}
+
+	recordAudit(ctx, uc.audit_repo, user.ID, client.IP, entity.AuditActionDeletionRequest, user.ID,
+		map[string]string{"delete_at": deleteAt.Format(time.RFC3339)})
+	return deleteAt, nil
+}
+
+// CancelDeletion reactivates an account pending deletion, as long as its
+// grace period has not ended. Its sessions were revoked by the request, so
+// the user authenticates with their credentials and logs in again afterwards.
+func (uc *UserUsecase) CancelDeletion(ctx context.Context, identification string, password string, client *dto.ClientInfo) (err error) {
+	ctx, end := startSpan(ctx, "UserUsecase.CancelDeletion")
+	defer end(&err)
+	user, err := uc.authenticate(ctx, identification, password)
+	if err != nil {
+		return err
+	}
+	if user.Status != entity.UserStatusPendingDeletion {
+		return errors.New("account is not pending deletion")
+	}
+	// Past the deadline the account is due to be erased, even if the purge
+	// job has not got to it yet.
+	if user.StatusExpiresAt != nil && !time.Now().UTC().Before(*user.StatusExpiresAt) {
+		return errors.New("deletion grace period has ended")
+	}
+
+	err = uc.user_repo.WithContext(ctx).SetStatus(user.ID, repointerfaces.AccountStatus{
+		Status:    entity.UserStatusActive,
+		ChangedBy: &user.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit_repo, user.ID, client.IP, entity.AuditActionDeletionCancel, user.ID, nil)
+	return nil
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"time"
+
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// effectiveStatus is the user's status at now. A suspension that has run out
+// counts as active even before the expiry job has lifted it.
+func effectiveStatus(user *entity.User, now time.Time) string {
+	if user.Status == "" {
+		return entity.UserStatusActive
+	}
+	if user.Status == entity.UserStatusSuspended && user.StatusExpiresAt != nil && !now.Before(*user.StatusExpiresAt) {
+		return entity.UserStatusActive
+	}
+	return user.Status
+}
+
+// checkAccountStatus returns an *AccountStatusError unless the user is active at now.
+func checkAccountStatus(user *entity.User, now time.Time) error {
+	status := effectiveStatus(user, now)
+	if status == entity.UserStatusActive {
+		return nil
+	}
+	return &usecaseinterfaces.AccountStatusError{
+		Status: status,
+		Reason: user.StatusReason,
+		Until:  user.StatusExpiresAt,
+	}
+}
+
+// checkAccountState is checkAccountStatus for the cached account state.
+func checkAccountState(state *repointerfaces.AccountState, now time.Time) error {
+	return checkAccountStatus(&entity.User{
+		Status:          state.Status,
+		StatusReason:    state.StatusReason,
+		StatusExpiresAt: state.StatusExpiresAt,
+	}, now)
+}
+
+// signOutEverywhere revokes every session of the user with reason, and every
+// personal access token, so no credential issued before survives.
+func signOutEverywhere(sessions repointerfaces.SessionRepoInterface, tokens repointerfaces.PersonalTokenRepoInterface, userID uuid.UUID, reason string) error {
+	if err := sessions.RevokeForAllUser(userID, reason); err != nil {
+		return err
+	}
+	return tokens.RevokeAllForUser(userID)
+}
+
+// statusRevokeReason is recorded on the sessions revoked when an account
+// leaves the active state.
+func statusRevokeReason(status string) string {
+	switch status {
+	case entity.UserStatusBanned:
+		return entity.RevokeReasonBanned
+	case entity.UserStatusDeactivated:
+		return entity.RevokeReasonDeactivated
+	default:
+		return entity.RevokeReasonSuspended
+	}
+}
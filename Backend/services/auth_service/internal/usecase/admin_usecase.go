@@ -0,0 +1,366 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"auth/internal/delivery/http/dto"
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultAdminPageSize = 20
+	maxAdminPageSize     = 100
+)
+
+// AdminUsecase implements the account management actions of the admin API.
+// Every change is recorded in the audit log.
+type AdminUsecase struct {
+	user_repo    repointerfaces.UserRepoInterface
+	session_repo repointerfaces.SessionRepoInterface
+	token_repo   repointerfaces.PersonalTokenRepoInterface
+	audit_repo   repointerfaces.AuditRepoInterface
+}
+
+func NewAdminUsecase(user_repo repointerfaces.UserRepoInterface, session_repo repointerfaces.SessionRepoInterface, token_repo repointerfaces.PersonalTokenRepoInterface, audit_repo repointerfaces.AuditRepoInterface) usecaseinterfaces.AdminUsecaseInterface {
+	return &AdminUsecase{user_repo: user_repo, session_repo: session_repo, token_repo: token_repo, audit_repo: audit_repo}
+}
+
+func (uc *AdminUsecase) ListUsers(ctx context.Context, query *dto.AdminUserQuery) (_ *dto.AdminUserListResponse, err error) {
+	ctx, end := startSpan(ctx, "AdminUsecase.ListUsers")
+	defer end(&err)
+	page, pageSize := pagination(query.Page, query.PageSize)
+	users, total, err := uc.user_repo.WithContext(ctx).Search(repointerfaces.UserFilter{
+		Query:    query.Query,
+		Role:     query.Role,
+		Status:   query.Status,
+		Verified: query.Verified,
+		Offset:   (page - 1) * pageSize,
+		Limit:    pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.AdminUserListResponse{Users: []*dto.AdminUserDTO{}, Page: page, PageSize: pageSize, Total: total}
+	for _, user := range users {
+		response.Users = append(response.Users, toAdminUserDTO(user))
+	}
+	return response, nil
+}
+
+func (uc *AdminUsecase) GetUser(ctx context.Context, userID uuid.UUID) (_ *dto.AdminUserDTO, err error) {
+	ctx, end := startSpan(ctx, "AdminUsecase.GetUser")
+	defer end(&err)
+	user, err := uc.user_repo.WithContext(ctx).GetById(userID)
+	if err != nil {
+		return nil, err
+	}
+	return toAdminUserDTO(user), nil
+}
+
+// SuspendUser blocks the account, until the given time when until is set,
+// and signs it out everywhere.
+func (uc *AdminUsecase) SuspendUser(ctx context.Context, actor *dto.AdminActor, userID uuid.UUID, reason string, until *time.Time) error {
+	return uc.ChangeStatus(ctx, actor, userID, entity.UserStatusSuspended, reason, until)
+}
+
+// UnsuspendUser lifts a suspension before it runs out.
+func (uc *AdminUsecase) UnsuspendUser(ctx context.Context, actor *dto.AdminActor, userID uuid.UUID) (err error) {
+	ctx, end := startSpan(ctx, "AdminUsecase.UnsuspendUser")
+	defer end(&err)
+	user, err := uc.targetUser(ctx, actor, userID)
+	if err != nil {
+		return err
+	}
+	if effectiveStatus(user, time.Now().UTC()) != entity.UserStatusSuspended {
+		return errors.New("user is not suspended")
+	}
+	return uc.applyStatus(ctx, actor, user, entity.UserStatusActive, "", nil)
+}
+
+// ChangeStatus moves the account to status. Leaving the active state revokes
+// every session of the account. Only suspensions may have an expiry;
+// suspending a suspended account replaces its reason and expiry.
+func (uc *AdminUsecase) ChangeStatus(ctx context.Context, actor *dto.AdminActor, userID uuid.UUID, status string, reason string, until *time.Time) (err error) {
+	ctx, end := startSpan(ctx, "AdminUsecase.ChangeStatus")
+	defer end(&err)
+	switch status {
+	case entity.UserStatusActive, entity.UserStatusSuspended, entity.UserStatusBanned, entity.UserStatusDeactivated:
+	default:
+		return errors.New("invalid status")
+	}
+	if until != nil {
+		if status != entity.UserStatusSuspended {
+			return errors.New("only suspensions can expire")
+		}
+		if !until.After(time.Now()) {
+			return errors.New("expiry must be in the future")
+		}
+	}
+
+	user, err := uc.targetUser(ctx, actor, userID)
+	if err != nil {
+		return err
+	}
+	if current := effectiveStatus(user, time.Now().UTC()); current == status && status != entity.UserStatusSuspended {
+		return errors.New("user is already " + status)
+	}
+	return uc.applyStatus(ctx, actor, user, status, reason, until)
+}
+
+func (uc *AdminUsecase) applyStatus(ctx context.Context, actor *dto.AdminActor, user *entity.User, status string, reason string, until *time.Time) error {
+	previous := effectiveStatus(user, time.Now().UTC())
+	// A blocked account must also be signed out, so the request going away
+	// must not stop the sequence between the two
+	ctx = context.WithoutCancel(ctx)
+	err := uc.user_repo.WithContext(ctx).SetStatus(user.ID, repointerfaces.AccountStatus{
+		Status:    status,
+		Reason:    reason,
+		ChangedBy: &actor.UserID,
+		ExpiresAt: until,
+	})
+	if err != nil {
+		return err
+	}
+	if status != entity.UserStatusActive {
+		if err := signOutEverywhere(uc.session_repo.WithContext(ctx), uc.token_repo.WithContext(ctx), user.ID, statusRevokeReason(status)); err != nil {
+			return err
+		}
+	}
+
+	details := map[string]string{"from": previous, "to": status}
+	if reason != "" {
+		details["reason"] = reason
+	}
+	if until != nil {
+		details["until"] = until.UTC().Format(time.RFC3339)
+	}
+	uc.audit(ctx, actor, statusAuditAction(previous, status), user.ID, details)
+	return nil
+}
+
+// statusAuditAction names a status change in the audit log.
+func statusAuditAction(from, to string) string {
+	switch to {
+	case entity.UserStatusSuspended:
+		return entity.AuditActionUserSuspend
+	case entity.UserStatusBanned:
+		return entity.AuditActionUserBan
+	case entity.UserStatusDeactivated:
+		return entity.AuditActionUserDeactivate
+	}
+	if from == entity.UserStatusSuspended {
+		return entity.AuditActionUserUnsuspend
+	}
+	return entity.AuditActionUserReactivate
+}
+
+// VerifyUser marks the account as verified without the usual verification flow.
+func (uc *AdminUsecase) VerifyUser(ctx context.Context, actor *dto.AdminActor, userID uuid.UUID) (err error) {
+	ctx, end := startSpan(ctx, "AdminUsecase.VerifyUser")
+	defer end(&err)
+	users := uc.user_repo.WithContext(ctx)
+	user, err := users.GetById(userID)
+	if err != nil {
+		return err
+	}
+	if user.IsVerified {
+		return errors.New("user is already verified")
+	}
+
+	if err := users.SetVerified(user.ID, true); err != nil {
+		return err
+	}
+	uc.audit(ctx, actor, entity.AuditActionUserVerify, user.ID, nil)
+	return nil
+}
+
+// ChangeRole gives the user a new role. Their sessions are revoked so that
+// access tokens carrying the old role stop working straight away.
+func (uc *AdminUsecase) ChangeRole(ctx context.Context, actor *dto.AdminActor, userID uuid.UUID, role string) (err error) {
+	ctx, end := startSpan(ctx, "AdminUsecase.ChangeRole")
+	defer end(&err)
+	switch role {
+	case entity.RoleUser, entity.RoleModerator, entity.RoleAdmin:
+	default:
+		return errors.New("invalid role")
+	}
+
+	user, err := uc.targetUser(ctx, actor, userID)
+	if err != nil {
+		return err
+	}
+	if user.Role == role {
+		return nil
+	}
+
+	// Tokens carrying the old role are revoked even if the request is
+	// cancelled once the role has changed
+	ctx = context.WithoutCancel(ctx)
+	if err := uc.user_repo.WithContext(ctx).UpdateRole(user.ID, role); err != nil {
+		return err
+	}
+	if err := signOutEverywhere(uc.session_repo.WithContext(ctx), uc.token_repo.WithContext(ctx), user.ID, entity.RevokeReasonRoleChanged); err != nil {
+		return err
+	}
+	uc.audit(ctx, actor, entity.AuditActionUserRole, user.ID, map[string]string{"from": user.Role, "to": role})
+	return nil
+}
+
+// PromoteToAdmin makes the user identified by email or username an admin.
+// It backs the "admin grant" command, which creates the first admin: the
+// admin API cannot, as it requires one. The change is audited with a nil
+// actor, standing for the command line.
+func (uc *AdminUsecase) PromoteToAdmin(ctx context.Context, identification string) (_ *dto.AdminUserDTO, err error) {
+	ctx, end := startSpan(ctx, "AdminUsecase.PromoteToAdmin")
+	defer end(&err)
+	users := uc.user_repo.WithContext(ctx)
+	user, err := users.GetByEmail(identification)
+	if err != nil {
+		user, err = users.GetByUsername(identification)
+		if err != nil {
+			return nil, errors.New("user not found")
+		}
+	}
+	if user.Role == entity.RoleAdmin {
+		return toAdminUserDTO(user), nil
+	}
+
+	if err := users.UpdateRole(user.ID, entity.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if err := signOutEverywhere(uc.session_repo.WithContext(ctx), uc.token_repo.WithContext(ctx), user.ID, entity.RevokeReasonRoleChanged); err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, uc.audit_repo, uuid.Nil, "", entity.AuditActionUserRole, user.ID, map[string]string{"from": user.Role, "to": entity.RoleAdmin, "via": "cli"})
+	user.Role = entity.RoleAdmin
+	return toAdminUserDTO(user), nil
+}
+
+// ForceLogout revokes every session of the user.
+func (uc *AdminUsecase) ForceLogout(ctx context.Context, actor *dto.AdminActor, userID uuid.UUID) (err error) {
+	ctx, end := startSpan(ctx, "AdminUsecase.ForceLogout")
+	defer end(&err)
+	user, err := uc.user_repo.WithContext(ctx).GetById(userID)
+	if err != nil {
+		return err
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	if err := signOutEverywhere(uc.session_repo.WithContext(ctx), uc.token_repo.WithContext(ctx), user.ID, entity.RevokeReasonAdminLogout); err != nil {
+		return err
+	}
+	uc.audit(ctx, actor, entity.AuditActionUserLogout, user.ID, nil)
+	return nil
+}
+
+func (uc *AdminUsecase) ListAuditLogs(ctx context.Context, query *dto.AuditLogQuery) (_ *dto.AuditLogListResponse, err error) {
+	ctx, end := startSpan(ctx, "AdminUsecase.ListAuditLogs")
+	defer end(&err)
+	page, pageSize := pagination(query.Page, query.PageSize)
+	filter := repointerfaces.AuditFilter{
+		Action: query.Action,
+		Offset: (page - 1) * pageSize,
+		Limit:  pageSize,
+	}
+	if query.ActorID != "" {
+		actorID, err := uuid.Parse(query.ActorID)
+		if err != nil {
+			return nil, errors.New("invalid actor id")
+		}
+		filter.ActorID = &actorID
+	}
+	if query.TargetID != "" {
+		targetID, err := uuid.Parse(query.TargetID)
+		if err != nil {
+			return nil, errors.New("invalid target id")
+		}
+		filter.TargetUserID = &targetID
+	}
+
+	entries, total, err := uc.audit_repo.WithContext(ctx).List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.AuditLogListResponse{Entries: []*dto.AuditLogDTO{}, Page: page, PageSize: pageSize, Total: total}
+	for _, entry := range entries {
+		item := &dto.AuditLogDTO{
+			ID:           entry.ID,
+			ActorID:      entry.ActorID,
+			Action:       entry.Action,
+			TargetUserID: entry.TargetUserID,
+			IP:           entry.IP,
+			CreatedAt:    entry.CreatedAt,
+		}
+		if err := json.Unmarshal([]byte(entry.Details), &item.Details); err != nil {
+			slog.WarnContext(ctx, "Unreadable details in audit log entry", "audit_id", entry.ID, "error", err)
+		}
+		response.Entries = append(response.Entries, item)
+	}
+	return response, nil
+}
+
+// targetUser loads the user an administrator wants to change. Administrators
+// cannot suspend or change the role of their own account, and erased
+// accounts cannot be changed at all.
+func (uc *AdminUsecase) targetUser(ctx context.Context, actor *dto.AdminActor, userID uuid.UUID) (*entity.User, error) {
+	if actor.UserID == userID {
+		return nil, errors.New("cannot change your own account")
+	}
+	user, err := uc.user_repo.WithContext(ctx).GetById(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status == entity.UserStatusDeleted {
+		return nil, errors.New("account has been deleted")
+	}
+	return user, nil
+}
+
+func (uc *AdminUsecase) audit(ctx context.Context, actor *dto.AdminActor, action string, targetID uuid.UUID, details map[string]string) {
+	recordAudit(ctx, uc.audit_repo, actor.UserID, actor.IP, action, targetID, details)
+}
+
+// pagination applies the default and maximum page size to page-based listings.
+func pagination(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultAdminPageSize
+	}
+	if pageSize > maxAdminPageSize {
+		pageSize = maxAdminPageSize
+	}
+	return page, pageSize
+}
+
+func toAdminUserDTO(user *entity.User) *dto.AdminUserDTO {
+	return &dto.AdminUserDTO{
+		ID:                    user.ID,
+		FullName:              user.FullName,
+		Email:                 user.Email,
+		Username:              user.Username,
+		PhoneNumber:           user.PhoneNumber,
+		Country:               user.Country,
+		Role:                  user.Role,
+		Status:                effectiveStatus(user, time.Now().UTC()),
+		StatusReason:          user.StatusReason,
+		StatusChangedBy:       user.StatusChangedBy,
+		StatusChangedAt:       user.StatusChangedAt,
+		StatusExpiresAt:       user.StatusExpiresAt,
+		IsVerified:            user.IsVerified,
+		PasswordResetRequired: user.PasswordResetRequired,
+		CreatedAt:             user.CreatedAt,
+		UpdatedAt:             user.UpdatedAt,
+	}
+}
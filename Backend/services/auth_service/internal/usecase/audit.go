@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// recordAudit records an action that has already been carried out. A failure
+// to write the entry is logged rather than reported, since the action itself
+// cannot be undone; for the same reason the entry is written even when ctx
+// has been cancelled.
+func recordAudit(ctx context.Context, repo repointerfaces.AuditRepoInterface, actorID uuid.UUID, ip string, action string, targetID uuid.UUID, details map[string]string) {
+	if details == nil {
+		details = map[string]string{}
+	}
+	encoded, err := json.Marshal(details)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to encode audit details", "action", action, "error", err)
+		encoded = []byte("{}")
+	}
+
+	entry := &entity.AuditLog{
+		ID:           uuid.New(),
+		ActorID:      actorID,
+		Action:       action,
+		TargetUserID: &targetID,
+		Details:      string(encoded),
+		IP:           ip,
+	}
+	if err := repo.WithContext(context.WithoutCancel(ctx)).Create(entry); err != nil {
+		slog.ErrorContext(ctx, "Failed to write audit log", "action", action, "actor_id", actorID, "target_id", targetID, "error", err)
+	}
+}
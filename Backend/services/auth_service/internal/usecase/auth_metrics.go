@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+	"auth/internal/infrastructure/metrics"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// hashPassword hashes a password with bcrypt, recording how long it took.
+func hashPassword(ctx context.Context, password string) (string, error) {
+	_, span := tracer.Start(ctx, "bcrypt.GenerateFromPassword")
+	defer span.End()
+	start := time.Now()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	metrics.PasswordHashDuration.WithLabelValues("hash").Observe(time.Since(start).Seconds())
+	return string(hash), err
+}
+
+// comparePassword checks a password against its bcrypt hash, recording how
+// long it took.
+func comparePassword(ctx context.Context, hash string, password string) error {
+	_, span := tracer.Start(ctx, "bcrypt.CompareHashAndPassword")
+	defer span.End()
+	start := time.Now()
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	metrics.PasswordHashDuration.WithLabelValues("compare").Observe(time.Since(start).Seconds())
+	return err
+}
+
+// loginOutcome labels the result of a login for the logins metric.
+func loginOutcome(err error) string {
+	if err == nil {
+		return "success"
+	}
+	var statusErr *usecaseinterfaces.AccountStatusError
+	switch {
+	case errors.As(err, &statusErr):
+		return "account_blocked"
+	case errors.Is(err, repointerfaces.ErrSessionLimitReached):
+		return "session_limit"
+	}
+	switch err.Error() {
+	case "user not found", "invalid credentials":
+		return "invalid_credentials"
+	case "password reset required":
+		return "password_reset_required"
+	}
+	return "error"
+}
+
+// refreshOutcome labels the result of a token refresh for the refreshes
+// metric.
+func refreshOutcome(err error) string {
+	if err == nil {
+		return "success"
+	}
+	var statusErr *usecaseinterfaces.AccountStatusError
+	switch {
+	case errors.As(err, &statusErr):
+		return "account_blocked"
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return "expired"
+	case errors.Is(err, gorm.ErrRecordNotFound),
+		strings.HasPrefix(err.Error(), "failed to parse refresh token"),
+		err.Error() == "invalid refresh token claims",
+		err.Error() == "refresh token mismatch":
+		return "invalid_token"
+	case err.Error() == "session expired or revoked":
+		return "revoked"
+	}
+	return "error"
+}
+
+// registrationOutcome labels the result of a registration for the
+// registrations metric.
+func registrationOutcome(err error) string {
+	if err == nil {
+		return "success"
+	}
+	switch err.Error() {
+	case "registration is closed":
+		return "closed"
+	case "invite code required", "invalid invite code":
+		return "invite_rejected"
+	}
+	return "error"
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"auth/internal/delivery/http/dto"
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuthzUsecase struct {
+	user_repo      repointerfaces.UserRepoInterface
+	community_repo repointerfaces.CommunityRepoInterface
+	policy         PermissionPolicy
+}
+
+func NewAuthzUsecase(user_repo repointerfaces.UserRepoInterface, community_repo repointerfaces.CommunityRepoInterface, policy PermissionPolicy) usecaseinterfaces.AuthzUsecaseInterface {
+	return &AuthzUsecase{user_repo: user_repo, community_repo: community_repo, policy: policy}
+}
+
+func (uc *AuthzUsecase) Check(ctx context.Context, request *dto.AuthzCheckRequest) (_ *dto.AuthzCheckResponse, err error) {
+	ctx, end := startSpan(ctx, "AuthzUsecase.Check")
+	defer end(&err)
+	if !slices.Contains(entity.Permissions, request.Action) {
+		return nil, errors.New("unknown action")
+	}
+
+	user, err := uc.user_repo.WithContext(ctx).GetById(request.Subject.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return denied("unknown subject"), nil
+		}
+		return nil, err
+	}
+	if status := effectiveStatus(user, time.Now().UTC()); status != entity.UserStatusActive {
+		return denied("account " + status), nil
+	}
+
+	var communityID *uuid.UUID
+	if request.Resource != nil {
+		communityID = &request.Resource.ID
+	}
+	return uc.evaluate(ctx, &dto.AuthzPrincipal{UserID: user.ID, Roles: []string{user.Role}}, request.Action, communityID)
+}
+
+func (uc *AuthzUsecase) Authorize(ctx context.Context, principal *dto.AuthzPrincipal, permission string, communityID *uuid.UUID) (_ *dto.AuthzCheckResponse, err error) {
+	ctx, end := startSpan(ctx, "AuthzUsecase.Authorize")
+	defer end(&err)
+	if !slices.Contains(entity.Permissions, permission) {
+		return nil, errors.New("unknown action")
+	}
+	return uc.evaluate(ctx, principal, permission, communityID)
+}
+
+// evaluate grants permission through the principal's global roles first, then
+// through their role in the community. The community role is always read from
+// the database: the one in an access token is as of when it was issued, and
+// may have changed since.
+func (uc *AuthzUsecase) evaluate(ctx context.Context, principal *dto.AuthzPrincipal, permission string, communityID *uuid.UUID) (*dto.AuthzCheckResponse, error) {
+	if allowed, reason := uc.policy.decide(principal.Roles, "", permission); allowed || communityID == nil {
+		return &dto.AuthzCheckResponse{Allowed: allowed, Reason: reason}, nil
+	}
+
+	membership, err := uc.community_repo.WithContext(ctx).GetMembership(*communityID, principal.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return denied("not a community member"), nil
+		}
+		return nil, err
+	}
+	allowed, reason := uc.policy.decide(principal.Roles, membership.Role, permission)
+	return &dto.AuthzCheckResponse{Allowed: allowed, Reason: reason}, nil
+}
+
+func denied(reason string) *dto.AuthzCheckResponse {
+	return &dto.AuthzCheckResponse{Allowed: false, Reason: reason}
+}
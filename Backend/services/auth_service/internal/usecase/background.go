@@ -0,0 +1,17 @@
+package usecase
+
+import "sync"
+
+// goTracked runs fn on its own goroutine. When tasks is set the goroutine is
+// counted in it, so shutdown can wait for work a request left running.
+func goTracked(tasks *sync.WaitGroup, fn func()) {
+	if tasks == nil {
+		go fn()
+		return
+	}
+	tasks.Add(1)
+	go func() {
+		defer tasks.Done()
+		fn()
+	}()
+}
@@ -0,0 +1,587 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"auth/internal/delivery/http/dto"
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+	"auth/internal/domain/entity"
+	"auth/internal/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var communitySlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// CommunityConfig holds the settings of community invitations.
+type CommunityConfig struct {
+	// InvitationTTL is how long an invitation can be accepted.
+	InvitationTTL time.Duration
+	Notifier      services.Notifier
+	// Tasks tracks the notifications sent in the background, so shutdown can
+	// wait for them.
+	Tasks *sync.WaitGroup
+}
+
+type CommunityUsecase struct {
+	community_repo    repointerfaces.CommunityRepoInterface
+	invitation_repo   repointerfaces.CommunityInvitationRepoInterface
+	join_request_repo repointerfaces.CommunityJoinRequestRepoInterface
+	user_repo         repointerfaces.UserRepoInterface
+	policy            PermissionPolicy
+	config            CommunityConfig
+}
+
+func NewCommunityUsecase(community_repo repointerfaces.CommunityRepoInterface, invitation_repo repointerfaces.CommunityInvitationRepoInterface, join_request_repo repointerfaces.CommunityJoinRequestRepoInterface, user_repo repointerfaces.UserRepoInterface, policy PermissionPolicy, config CommunityConfig) usecaseinterfaces.CommunityUsecaseInterface {
+	return &CommunityUsecase{
+		community_repo:    community_repo,
+		invitation_repo:   invitation_repo,
+		join_request_repo: join_request_repo,
+		user_repo:         user_repo,
+		policy:            policy,
+		config:            config,
+	}
+}
+
+func (uc *CommunityUsecase) CreateCommunity(ctx context.Context, userID uuid.UUID, request *dto.CreateCommunityRequest) (_ *dto.CommunityDTO, err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.CreateCommunity")
+	defer end(&err)
+	slug := strings.ToLower(strings.TrimSpace(request.Slug))
+	if !communitySlugPattern.MatchString(slug) {
+		return nil, errors.New("invalid slug")
+	}
+	if _, err := uc.community_repo.WithContext(ctx).GetBySlug(slug); err == nil {
+		return nil, errors.New("slug already taken")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	community := &entity.Community{
+		ID:          uuid.New(),
+		Name:        strings.TrimSpace(request.Name),
+		Slug:        slug,
+		Description: request.Description,
+		CreatedBy:   &userID,
+	}
+	if err := uc.community_repo.WithContext(ctx).Create(community, userID); err != nil {
+		return nil, err
+	}
+	return toCommunityDTO(community), nil
+}
+
+func (uc *CommunityUsecase) GetCommunity(ctx context.Context, communityID uuid.UUID) (_ *dto.CommunityDTO, err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.GetCommunity")
+	defer end(&err)
+	community, err := uc.community_repo.WithContext(ctx).GetById(communityID)
+	if err != nil {
+		return nil, err
+	}
+	return toCommunityDTO(community), nil
+}
+
+func (uc *CommunityUsecase) DeleteCommunity(ctx context.Context, userID uuid.UUID, communityID uuid.UUID) (err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.DeleteCommunity")
+	defer end(&err)
+	if _, err := uc.requirePermission(ctx, communityID, userID, entity.PermissionCommunityDelete); err != nil {
+		return err
+	}
+	return uc.community_repo.WithContext(ctx).Delete(communityID)
+}
+
+func (uc *CommunityUsecase) ListMyCommunities(ctx context.Context, userID uuid.UUID) (_ []*dto.MyCommunityDTO, err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.ListMyCommunities")
+	defer end(&err)
+	memberships, err := uc.community_repo.WithContext(ctx).ListForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	communities := []*dto.MyCommunityDTO{}
+	for _, membership := range memberships {
+		communities = append(communities, &dto.MyCommunityDTO{
+			CommunityDTO: *toCommunityDTO(&membership.Community),
+			Role:         membership.Role,
+			JoinedAt:     membership.CreatedAt,
+		})
+	}
+	return communities, nil
+}
+
+func (uc *CommunityUsecase) ListMembers(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, query *dto.CommunityMemberQuery) (_ *dto.CommunityMemberListResponse, err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.ListMembers")
+	defer end(&err)
+	if _, err := uc.requirePermission(ctx, communityID, userID, entity.PermissionMembersRead); err != nil {
+		return nil, err
+	}
+
+	page, pageSize := pagination(query.Page, query.PageSize)
+	memberships, total, err := uc.community_repo.WithContext(ctx).ListMembers(communityID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []*dto.CommunityMemberDTO{}
+	for _, membership := range memberships {
+		members = append(members, &dto.CommunityMemberDTO{
+			UserID:   membership.UserID,
+			Username: membership.User.Username,
+			FullName: membership.User.FullName,
+			Role:     membership.Role,
+			JoinedAt: membership.CreatedAt,
+		})
+	}
+	return &dto.CommunityMemberListResponse{Members: members, Page: page, PageSize: pageSize, Total: total}, nil
+}
+
+// ChangeMemberRole is reserved to owners. The last owner cannot be demoted.
+func (uc *CommunityUsecase) ChangeMemberRole(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, memberID uuid.UUID, role string) (err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.ChangeMemberRole")
+	defer end(&err)
+	if _, err := uc.requirePermission(ctx, communityID, userID, entity.PermissionMembersManageRoles); err != nil {
+		return err
+	}
+	member, err := uc.community_repo.WithContext(ctx).GetMembership(communityID, memberID)
+	if err != nil {
+		return err
+	}
+	if member.Role == role {
+		return nil
+	}
+	return uc.community_repo.WithContext(ctx).UpdateMemberRole(communityID, memberID, role)
+}
+
+// RemoveMember takes someone out of the community. Moderators may only
+// remove plain members; owners may remove anyone but the last owner.
+func (uc *CommunityUsecase) RemoveMember(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, memberID uuid.UUID) (err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.RemoveMember")
+	defer end(&err)
+	if userID == memberID {
+		return uc.Leave(ctx, userID, communityID)
+	}
+	actor, err := uc.requirePermission(ctx, communityID, userID, entity.PermissionMembersRemove)
+	if err != nil {
+		return err
+	}
+	member, err := uc.community_repo.WithContext(ctx).GetMembership(communityID, memberID)
+	if err != nil {
+		return err
+	}
+	if member.Role != entity.CommunityRoleMember && !uc.can(actor, entity.PermissionMembersManageRoles) {
+		return errors.New("insufficient community role")
+	}
+	return uc.community_repo.WithContext(ctx).RemoveMember(communityID, memberID)
+}
+
+func (uc *CommunityUsecase) Leave(ctx context.Context, userID uuid.UUID, communityID uuid.UUID) (err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.Leave")
+	defer end(&err)
+	if _, err := uc.requireMembership(ctx, communityID, userID); err != nil {
+		return err
+	}
+	return uc.community_repo.WithContext(ctx).RemoveMember(communityID, userID)
+}
+
+// Invite invites an existing user by email or username. Moderators may only
+// invite members; owners may also invite moderators.
+func (uc *CommunityUsecase) Invite(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, request *dto.CreateInvitationRequest) (_ *dto.InvitationDTO, err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.Invite")
+	defer end(&err)
+	actor, err := uc.requirePermission(ctx, communityID, userID, entity.PermissionMembersInvite)
+	if err != nil {
+		return nil, err
+	}
+	role := request.Role
+	if role == "" {
+		role = entity.CommunityRoleMember
+	}
+	if role != entity.CommunityRoleMember && !uc.can(actor, entity.PermissionMembersManageRoles) {
+		return nil, errors.New("insufficient community role")
+	}
+
+	invitee, err := uc.user_repo.WithContext(ctx).GetByEmail(request.Identification)
+	if err != nil {
+		invitee, err = uc.user_repo.WithContext(ctx).GetByUsername(request.Identification)
+		if err != nil {
+			return nil, errors.New("user not found")
+		}
+	}
+	if _, err := uc.community_repo.WithContext(ctx).GetMembership(communityID, invitee.ID); err == nil {
+		return nil, errors.New("already a member")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if _, err := uc.invitation_repo.WithContext(ctx).FindPending(communityID, invitee.ID, now); err == nil {
+		return nil, errors.New("invitation already pending")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	invitation := &entity.CommunityInvitation{
+		ID:          uuid.New(),
+		CommunityID: communityID,
+		InviteeID:   invitee.ID,
+		InvitedBy:   userID,
+		Role:        role,
+		Status:      entity.InvitationStatusPending,
+		ExpiresAt:   now.Add(uc.config.InvitationTTL),
+		CreatedAt:   now,
+	}
+	if err := uc.invitation_repo.WithContext(ctx).Create(invitation); err != nil {
+		return nil, err
+	}
+	goTracked(uc.config.Tasks, func() { uc.notifyInvitation(ctx, invitation, invitee) })
+
+	invitationDto := toInvitationDTO(invitation)
+	invitationDto.Invitee = invitee.Username
+	return invitationDto, nil
+}
+
+func (uc *CommunityUsecase) ListInvitations(ctx context.Context, userID uuid.UUID, communityID uuid.UUID) (_ []*dto.InvitationDTO, err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.ListInvitations")
+	defer end(&err)
+	if _, err := uc.requirePermission(ctx, communityID, userID, entity.PermissionMembersInvite); err != nil {
+		return nil, err
+	}
+	invitations, err := uc.invitation_repo.WithContext(ctx).ListPendingForCommunity(communityID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	invitationsDto := []*dto.InvitationDTO{}
+	for _, invitation := range invitations {
+		invitationDto := toInvitationDTO(invitation)
+		invitationDto.Invitee = invitation.Invitee.Username
+		invitationsDto = append(invitationsDto, invitationDto)
+	}
+	return invitationsDto, nil
+}
+
+func (uc *CommunityUsecase) RevokeInvitation(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, invitationID uuid.UUID) (err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.RevokeInvitation")
+	defer end(&err)
+	if _, err := uc.requirePermission(ctx, communityID, userID, entity.PermissionMembersInvite); err != nil {
+		return err
+	}
+	invitation, err := uc.invitation_repo.WithContext(ctx).GetById(invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.CommunityID != communityID {
+		return gorm.ErrRecordNotFound
+	}
+	return uc.closeInvitation(ctx, invitation.ID, entity.InvitationStatusRevoked)
+}
+
+func (uc *CommunityUsecase) ListMyInvitations(ctx context.Context, userID uuid.UUID) (_ []*dto.InvitationDTO, err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.ListMyInvitations")
+	defer end(&err)
+	invitations, err := uc.invitation_repo.WithContext(ctx).ListPendingForUser(userID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	invitationsDto := []*dto.InvitationDTO{}
+	for _, invitation := range invitations {
+		invitationDto := toInvitationDTO(invitation)
+		invitationDto.Community = toCommunityDTO(&invitation.Community)
+		invitationsDto = append(invitationsDto, invitationDto)
+	}
+	return invitationsDto, nil
+}
+
+func (uc *CommunityUsecase) AcceptInvitation(ctx context.Context, userID uuid.UUID, invitationID uuid.UUID) (err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.AcceptInvitation")
+	defer end(&err)
+	invitation, err := uc.openInvitation(ctx, userID, invitationID)
+	if err != nil {
+		return err
+	}
+	accepted, err := uc.invitation_repo.WithContext(ctx).Accept(invitation.ID, &entity.CommunityMembership{
+		CommunityID: invitation.CommunityID,
+		UserID:      userID,
+		Role:        invitation.Role,
+	})
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return errors.New("invitation is no longer pending")
+	}
+	return nil
+}
+
+func (uc *CommunityUsecase) DeclineInvitation(ctx context.Context, userID uuid.UUID, invitationID uuid.UUID) (err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.DeclineInvitation")
+	defer end(&err)
+	invitation, err := uc.openInvitation(ctx, userID, invitationID)
+	if err != nil {
+		return err
+	}
+	return uc.closeInvitation(ctx, invitation.ID, entity.InvitationStatusDeclined)
+}
+
+func (uc *CommunityUsecase) RequestToJoin(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, request *dto.CreateJoinRequestRequest) (_ *dto.JoinRequestDTO, err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.RequestToJoin")
+	defer end(&err)
+	if _, err := uc.community_repo.WithContext(ctx).GetById(communityID); err != nil {
+		return nil, err
+	}
+	if _, err := uc.community_repo.WithContext(ctx).GetMembership(communityID, userID); err == nil {
+		return nil, errors.New("already a member")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if _, err := uc.join_request_repo.WithContext(ctx).FindPending(communityID, userID); err == nil {
+		return nil, errors.New("join request already pending")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	joinRequest := &entity.CommunityJoinRequest{
+		ID:          uuid.New(),
+		CommunityID: communityID,
+		UserID:      userID,
+		Message:     request.Message,
+		Status:      entity.JoinRequestStatusPending,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := uc.join_request_repo.WithContext(ctx).Create(joinRequest); err != nil {
+		return nil, err
+	}
+	return toJoinRequestDTO(joinRequest), nil
+}
+
+func (uc *CommunityUsecase) ListJoinRequests(ctx context.Context, userID uuid.UUID, communityID uuid.UUID) (_ []*dto.JoinRequestDTO, err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.ListJoinRequests")
+	defer end(&err)
+	if _, err := uc.requirePermission(ctx, communityID, userID, entity.PermissionJoinRequestsReview); err != nil {
+		return nil, err
+	}
+	requests, err := uc.join_request_repo.WithContext(ctx).ListPending(communityID)
+	if err != nil {
+		return nil, err
+	}
+	requestsDto := []*dto.JoinRequestDTO{}
+	for _, request := range requests {
+		requestDto := toJoinRequestDTO(request)
+		requestDto.Username = request.User.Username
+		requestsDto = append(requestsDto, requestDto)
+	}
+	return requestsDto, nil
+}
+
+func (uc *CommunityUsecase) ApproveJoinRequest(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, requestID uuid.UUID) (err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.ApproveJoinRequest")
+	defer end(&err)
+	request, err := uc.pendingJoinRequest(ctx, userID, communityID, requestID)
+	if err != nil {
+		return err
+	}
+	approved, err := uc.join_request_repo.WithContext(ctx).Approve(request.ID, userID, &entity.CommunityMembership{
+		CommunityID: communityID,
+		UserID:      request.UserID,
+		Role:        entity.CommunityRoleMember,
+	})
+	if err != nil {
+		return err
+	}
+	if !approved {
+		return errors.New("join request is no longer pending")
+	}
+	return nil
+}
+
+func (uc *CommunityUsecase) RejectJoinRequest(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, requestID uuid.UUID) (err error) {
+	ctx, end := startSpan(ctx, "CommunityUsecase.RejectJoinRequest")
+	defer end(&err)
+	request, err := uc.pendingJoinRequest(ctx, userID, communityID, requestID)
+	if err != nil {
+		return err
+	}
+	rejected, err := uc.join_request_repo.WithContext(ctx).Reject(request.ID, userID)
+	if err != nil {
+		return err
+	}
+	if !rejected {
+		return errors.New("join request is no longer pending")
+	}
+	return nil
+}
+
+// requireMembership returns the user's membership in the community. A
+// missing community is reported as gorm.ErrRecordNotFound.
+func (uc *CommunityUsecase) requireMembership(ctx context.Context, communityID uuid.UUID, userID uuid.UUID) (*entity.CommunityMembership, error) {
+	membership, err := uc.community_repo.WithContext(ctx).GetMembership(communityID, userID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if _, err := uc.community_repo.WithContext(ctx).GetById(communityID); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("not a community member")
+	}
+	return membership, nil
+}
+
+// communityActor is a user acting on a community: their global roles, and
+// their membership there when they have one.
+type communityActor struct {
+	roles      []string
+	membership *entity.CommunityMembership
+}
+
+// can evaluates permission for actor with the same policy as AuthzUsecase,
+// so global roles such as admin apply to communities too.
+func (uc *CommunityUsecase) can(actor *communityActor, permission string) bool {
+	communityRole := ""
+	if actor.membership != nil {
+		communityRole = actor.membership.Role
+	}
+	allowed, _ := uc.policy.decide(actor.roles, communityRole, permission)
+	return allowed
+}
+
+// requirePermission returns the user as an actor in the community when their
+// global roles or their role in the community grant permission.
+func (uc *CommunityUsecase) requirePermission(ctx context.Context, communityID uuid.UUID, userID uuid.UUID, permission string) (*communityActor, error) {
+	user, err := uc.user_repo.WithContext(ctx).GetById(userID)
+	if err != nil {
+		return nil, err
+	}
+	actor := &communityActor{roles: []string{user.Role}}
+	membership, err := uc.requireMembership(ctx, communityID, userID)
+	if err == nil {
+		actor.membership = membership
+	} else if err.Error() != "not a community member" {
+		return nil, err
+	}
+
+	if !uc.can(actor, permission) {
+		if actor.membership == nil {
+			return nil, errors.New("not a community member")
+		}
+		return nil, errors.New("insufficient community role")
+	}
+	return actor, nil
+}
+
+// openInvitation loads a pending, unexpired invitation addressed to userID.
+// Other users' invitations are reported as gorm.ErrRecordNotFound.
+func (uc *CommunityUsecase) openInvitation(ctx context.Context, userID uuid.UUID, invitationID uuid.UUID) (*entity.CommunityInvitation, error) {
+	invitation, err := uc.invitation_repo.WithContext(ctx).GetById(invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.InviteeID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if invitation.Status != entity.InvitationStatusPending || !time.Now().UTC().Before(invitation.ExpiresAt) {
+		return nil, errors.New("invitation is no longer pending")
+	}
+	return invitation, nil
+}
+
+func (uc *CommunityUsecase) closeInvitation(ctx context.Context, invitationID uuid.UUID, status string) error {
+	closed, err := uc.invitation_repo.WithContext(ctx).Close(invitationID, status)
+	if err != nil {
+		return err
+	}
+	if !closed {
+		return errors.New("invitation is no longer pending")
+	}
+	return nil
+}
+
+func (uc *CommunityUsecase) pendingJoinRequest(ctx context.Context, userID uuid.UUID, communityID uuid.UUID, requestID uuid.UUID) (*entity.CommunityJoinRequest, error) {
+	if _, err := uc.requirePermission(ctx, communityID, userID, entity.PermissionJoinRequestsReview); err != nil {
+		return nil, err
+	}
+	request, err := uc.join_request_repo.WithContext(ctx).GetById(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.CommunityID != communityID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if request.Status != entity.JoinRequestStatusPending {
+		return nil, errors.New("join request is no longer pending")
+	}
+	return request, nil
+}
+
+// notifyInvitation runs in the background, so it detaches from the
+// cancellation of ctx but keeps its request ID and trace.
+func (uc *CommunityUsecase) notifyInvitation(ctx context.Context, invitation *entity.CommunityInvitation, invitee *entity.User) {
+	ctx = context.WithoutCancel(ctx)
+	community, err := uc.community_repo.WithContext(ctx).GetById(invitation.CommunityID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load community for invitation", "community_id", invitation.CommunityID, "invitation_id", invitation.ID, "error", err)
+		return
+	}
+	expires := invitation.ExpiresAt.UTC().Format(time.RFC1123)
+
+	notification := services.Notification{
+		Kind:    "community_invitation",
+		UserID:  invitee.ID,
+		Email:   invitee.Email,
+		Subject: fmt.Sprintf("You have been invited to %s", community.Name),
+		Body: fmt.Sprintf("Hi %s,\n\nYou have been invited to join %s as a %s. "+
+			"Sign in to accept or decline the invitation before %s.\n",
+			invitee.FullName, community.Name, invitation.Role, expires),
+		Data: map[string]string{
+			"invitation_id": invitation.ID.String(),
+			"community_id":  community.ID.String(),
+			"role":          invitation.Role,
+			"expires_at":    expires,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, alertSendTimeout)
+	defer cancel()
+	if err := uc.config.Notifier.Notify(ctx, notification); err != nil {
+		slog.ErrorContext(ctx, "Failed to send invitation", "invitation_id", invitation.ID, "user_id", invitee.ID, "error", err)
+	}
+}
+
+func toCommunityDTO(community *entity.Community) *dto.CommunityDTO {
+	return &dto.CommunityDTO{
+		ID:          community.ID,
+		Name:        community.Name,
+		Slug:        community.Slug,
+		Description: community.Description,
+		CreatedAt:   community.CreatedAt,
+	}
+}
+
+func toInvitationDTO(invitation *entity.CommunityInvitation) *dto.InvitationDTO {
+	return &dto.InvitationDTO{
+		ID:          invitation.ID,
+		CommunityID: invitation.CommunityID,
+		InviteeID:   invitation.InviteeID,
+		InvitedBy:   invitation.InvitedBy,
+		Role:        invitation.Role,
+		Status:      invitation.Status,
+		ExpiresAt:   invitation.ExpiresAt,
+		CreatedAt:   invitation.CreatedAt,
+	}
+}
+
+func toJoinRequestDTO(request *entity.CommunityJoinRequest) *dto.JoinRequestDTO {
+	return &dto.JoinRequestDTO{
+		ID:          request.ID,
+		CommunityID: request.CommunityID,
+		UserID:      request.UserID,
+		Message:     request.Message,
+		Status:      request.Status,
+		CreatedAt:   request.CreatedAt,
+		DecidedAt:   request.DecidedAt,
+	}
+}
@@ -0,0 +1,419 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"auth/internal/delivery/http/dto"
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+	"auth/internal/domain/entity"
+	"auth/internal/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// exportStaleAfter is how long an export may sit in processing before
+	// another worker assumes its builder died and takes it over.
+	exportStaleAfter = 15 * time.Minute
+	// exportPageSize is how many sessions or audit entries are read per query
+	// while building an archive.
+	exportPageSize = 500
+)
+
+// DataExportConfig configures personal data exports.
+type DataExportConfig struct {
+	// Dir is where archives are written.
+	Dir string
+	// TTL is how long an archive and its download link stay available.
+	TTL time.Duration
+	// BaseURL is the public URL of this service, used to build download links.
+	BaseURL  string
+	Notifier services.Notifier
+	// Tasks tracks the notifications sent in the background, so shutdown can
+	// wait for them.
+	Tasks *sync.WaitGroup
+}
+
+// DataExportUsecase builds JSON archives of a user's personal data in the
+// background and hands them out through signed, expiring links.
+type DataExportUsecase struct {
+	export_repo  repointerfaces.DataExportRepoInterface
+	user_repo    repointerfaces.UserRepoInterface
+	session_repo repointerfaces.SessionRepoInterface
+	audit_repo   repointerfaces.AuditRepoInterface
+	tokenservice services.TokenService
+	policy       SessionPolicy
+	config       DataExportConfig
+}
+
+func NewDataExportUsecase(export_repo repointerfaces.DataExportRepoInterface, user_repo repointerfaces.UserRepoInterface, session_repo repointerfaces.SessionRepoInterface, audit_repo repointerfaces.AuditRepoInterface, tokenservice services.TokenService, policy SessionPolicy, config DataExportConfig) usecaseinterfaces.DataExportUsecaseInterface {
+	return &DataExportUsecase{
+		export_repo:  export_repo,
+		user_repo:    user_repo,
+		session_repo: session_repo,
+		audit_repo:   audit_repo,
+		tokenservice: tokenservice,
+		policy:       policy,
+		config:       config,
+	}
+}
+
+// RequestExport queues an export of the user's data. A user can only have
+// one export in progress at a time.
+func (uc *DataExportUsecase) RequestExport(ctx context.Context, userID uuid.UUID) (_ *dto.DataExportDTO, err error) {
+	ctx, end := startSpan(ctx, "DataExportUsecase.RequestExport")
+	defer end(&err)
+	export := &entity.DataExport{
+		ID:     uuid.New(),
+		UserID: userID,
+		Status: entity.ExportStatusPending,
+	}
+	if err := uc.export_repo.WithContext(ctx).Create(export); err != nil {
+		return nil, err
+	}
+	return uc.toDTO(export)
+}
+
+// GetExport reports the state of one of the user's exports. Exports of other
+// users are reported as gorm.ErrRecordNotFound.
+func (uc *DataExportUsecase) GetExport(ctx context.Context, userID uuid.UUID, exportID uuid.UUID) (_ *dto.DataExportDTO, err error) {
+	ctx, end := startSpan(ctx, "DataExportUsecase.GetExport")
+	defer end(&err)
+	export, err := uc.export_repo.WithContext(ctx).GetById(exportID)
+	if err != nil {
+		return nil, err
+	}
+	if export.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return uc.toDTO(export)
+}
+
+func (uc *DataExportUsecase) OpenDownload(ctx context.Context, token string) (_ string, _ string, err error) {
+	ctx, end := startSpan(ctx, "DataExportUsecase.OpenDownload")
+	defer end(&err)
+	claims, err := uc.tokenservice.ParseActionToken(token, services.PurposeDataExport)
+	if err != nil {
+		return "", "", errors.New("invalid or expired link")
+	}
+	exportID, err := uuid.Parse(claims.Binding)
+	if err != nil {
+		return "", "", errors.New("invalid or expired link")
+	}
+
+	export, err := uc.export_repo.WithContext(ctx).GetById(exportID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", "", errors.New("invalid or expired link")
+		}
+		return "", "", err
+	}
+	if export.UserID != claims.UserID || export.Status != entity.ExportStatusReady ||
+		export.ExpiresAt == nil || !time.Now().Before(*export.ExpiresAt) {
+		return "", "", errors.New("invalid or expired link")
+	}
+
+	filename := fmt.Sprintf("personal-data-%s.json", export.CompletedAt.UTC().Format("2006-01-02"))
+	return uc.archivePath(export.ID), filename, nil
+}
+
+func (uc *DataExportUsecase) ProcessNext(ctx context.Context) (_ bool, err error) {
+	ctx, end := startSpan(ctx, "DataExportUsecase.ProcessNext")
+	defer end(&err)
+	export, err := uc.export_repo.WithContext(ctx).ClaimNext(time.Now().UTC().Add(-exportStaleAfter))
+	if err != nil || export == nil {
+		return false, err
+	}
+	// Once claimed, the export's outcome is recorded even if ctx is cancelled
+	// meanwhile, so it is not left processing until it goes stale
+	exports := uc.export_repo.WithContext(context.WithoutCancel(ctx))
+
+	if err := uc.writeArchive(ctx, export); err != nil {
+		if ctx.Err() != nil {
+			// Interrupted by shutdown rather than failed: queue it again so
+			// the next worker builds it.
+			if requeueErr := exports.Requeue(export.ID); requeueErr != nil {
+				slog.WarnContext(ctx, "Failed to requeue interrupted data export, it will be reclaimed once stale", "export_id", export.ID, "error", requeueErr)
+			}
+			return true, nil
+		}
+		slog.ErrorContext(ctx, "Failed to build data export", "export_id", export.ID, "error", err)
+		if markErr := exports.MarkFailed(export.ID, "the archive could not be built", time.Now().UTC().Add(uc.config.TTL)); markErr != nil {
+			return true, markErr
+		}
+		return true, nil
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(uc.config.TTL)
+	if err := exports.MarkReady(export.ID, now, expiresAt); err != nil {
+		return true, err
+	}
+	export.Status, export.CompletedAt, export.ExpiresAt = entity.ExportStatusReady, &now, &expiresAt
+	goTracked(uc.config.Tasks, func() { uc.notifyReady(ctx, export) })
+	return true, nil
+}
+
+// PurgeExpired deletes expired exports and their archives, and any archive
+// left on disk for longer than the TTL, such as one finished for an account
+// erased while it was being built.
+func (uc *DataExportUsecase) PurgeExpired(ctx context.Context, now time.Time) (_ int, err error) {
+	ctx, end := startSpan(ctx, "DataExportUsecase.PurgeExpired")
+	defer end(&err)
+	ids, err := uc.export_repo.WithContext(ctx).DeleteExpired(now)
+	if err != nil {
+		return 0, err
+	}
+	uc.RemoveArchives(ids)
+
+	entries, err := os.ReadDir(uc.config.Dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return len(ids), err
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || now.Sub(info.ModTime()) < uc.config.TTL {
+			continue
+		}
+		if err := os.Remove(filepath.Join(uc.config.Dir, entry.Name())); err != nil {
+			slog.ErrorContext(ctx, "Failed to remove stale data export file", "file", entry.Name(), "error", err)
+		}
+	}
+	return len(ids), nil
+}
+
+// RemoveArchives deletes the archives of exports that no longer exist.
+func (uc *DataExportUsecase) RemoveArchives(exportIDs []uuid.UUID) {
+	for _, id := range exportIDs {
+		if err := os.Remove(uc.archivePath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.Error("Failed to remove data export archive", "export_id", id, "error", err)
+		}
+	}
+}
+
+// writeArchive builds the archive into a temporary file and renames it into
+// place, so a download never sees a partial archive.
+func (uc *DataExportUsecase) writeArchive(ctx context.Context, export *entity.DataExport) error {
+	archive, err := uc.collect(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(uc.config.Dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(uc.config.Dir, ".export-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	encoder := json.NewEncoder(tmp)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(archive); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), uc.archivePath(export.ID))
+}
+
+// collect gathers everything stored about the user.
+func (uc *DataExportUsecase) collect(ctx context.Context, userID uuid.UUID) (*dto.PersonalDataArchive, error) {
+	user, err := uc.user_repo.WithContext(ctx).GetById(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	archive := &dto.PersonalDataArchive{
+		GeneratedAt: now,
+		Profile: dto.ExportedProfile{
+			ID:              user.ID,
+			FullName:        user.FullName,
+			Email:           user.Email,
+			Username:        user.Username,
+			PhoneNumber:     user.PhoneNumber,
+			Country:         user.Country,
+			Role:            user.Role,
+			Status:          effectiveStatus(user, now),
+			StatusReason:    user.StatusReason,
+			StatusChangedAt: user.StatusChangedAt,
+			StatusExpiresAt: user.StatusExpiresAt,
+			IsVerified:      user.IsVerified,
+			CreatedAt:       user.CreatedAt,
+			UpdatedAt:       user.UpdatedAt,
+		},
+		Consents: []dto.ExportedConsent{
+			{Name: "terms_of_service", Accepted: user.AcceptedTerms},
+		},
+		Sessions:         []dto.SessionHistoryItemDTO{},
+		ArchivedSessions: []dto.SessionHistoryItemDTO{},
+		SecurityEvents:   []dto.ExportedSecurityEvent{},
+	}
+
+	sessionRepo := uc.session_repo.WithContext(ctx)
+	// Archived sessions are listed on their own below
+	filter := repointerfaces.SessionHistoryFilter{Limit: exportPageSize, ExcludeArchived: true}
+	for ctx.Err() == nil {
+		sessions, err := sessionRepo.ListHistory(userID, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, session := range sessions {
+			archive.Sessions = append(archive.Sessions, dto.SessionHistoryItemDTO{
+				SessionResponseDTO: *toSessionDTO(session),
+				Status:             uc.policy.sessionStatus(session, now),
+				CreatedAt:          session.CreatedAt,
+				ExpiresAt:          session.ExpiresAt,
+				RevokedAt:          session.RevokedAt,
+				RevokedReason:      session.RevokedReason,
+			})
+		}
+		if len(sessions) < exportPageSize {
+			break
+		}
+		last := sessions[len(sessions)-1]
+		filter.AfterCreatedAt, filter.AfterID = last.CreatedAt, &last.ID
+	}
+
+	archived, err := sessionRepo.ListArchived(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range archived {
+		status := dto.SessionStatusExpired
+		if session.RevokedAt != nil {
+			status = dto.SessionStatusRevoked
+		}
+		archive.ArchivedSessions = append(archive.ArchivedSessions, dto.SessionHistoryItemDTO{
+			SessionResponseDTO: dto.SessionResponseDTO{
+				ID:         session.ID,
+				UserID:     session.UserID,
+				UserAgent:  session.UserAgent,
+				IP:         session.IP,
+				Browser:    session.Browser,
+				OS:         session.OS,
+				DeviceType: session.DeviceType,
+				DeviceName: session.DeviceName,
+				LastUsedAt: session.LastUsedAt,
+			},
+			Status:        status,
+			CreatedAt:     session.CreatedAt,
+			ExpiresAt:     session.ExpiresAt,
+			RevokedAt:     session.RevokedAt,
+			RevokedReason: session.RevokedReason,
+		})
+	}
+
+	for offset := 0; ctx.Err() == nil; offset += exportPageSize {
+		entries, _, err := uc.audit_repo.WithContext(ctx).List(repointerfaces.AuditFilter{SubjectID: &userID, Offset: offset, Limit: exportPageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			event := dto.ExportedSecurityEvent{Action: entry.Action, PerformedBy: "administrator", CreatedAt: entry.CreatedAt}
+			if entry.ActorID == userID {
+				event.PerformedBy, event.IP = "you", entry.IP
+			}
+			if err := json.Unmarshal([]byte(entry.Details), &event.Details); err != nil {
+				slog.WarnContext(ctx, "Unreadable details in audit log entry", "audit_id", entry.ID, "error", err)
+			}
+			archive.SecurityEvents = append(archive.SecurityEvents, event)
+		}
+		if len(entries) < exportPageSize {
+			break
+		}
+	}
+
+	return archive, ctx.Err()
+}
+
+// notifyReady runs in the background and may outlive ctx, the worker's
+// context, whose trace it keeps.
+func (uc *DataExportUsecase) notifyReady(ctx context.Context, export *entity.DataExport) {
+	ctx = context.WithoutCancel(ctx)
+	user, err := uc.user_repo.WithContext(ctx).GetById(export.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load user to announce data export", "user_id", export.UserID, "export_id", export.ID, "error", err)
+		return
+	}
+	link, err := uc.downloadURL(export)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to create download link for data export", "export_id", export.ID, "error", err)
+		return
+	}
+	expires := export.ExpiresAt.UTC().Format(time.RFC1123)
+
+	notification := services.Notification{
+		Kind:    "data_export_ready",
+		UserID:  user.ID,
+		Email:   user.Email,
+		Subject: "Your personal data export is ready",
+		Body: fmt.Sprintf("Hi %s,\n\nThe copy of your personal data you asked for is ready. "+
+			"Download it from the link below before %s:\n%s\n\n"+
+			"If you did not ask for this export, change your password and sign out of your other devices.\n",
+			user.FullName, expires, link),
+		Data: map[string]string{
+			"export_id":    export.ID.String(),
+			"download_url": link,
+			"expires_at":   expires,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, alertSendTimeout)
+	defer cancel()
+	if err := uc.config.Notifier.Notify(ctx, notification); err != nil {
+		slog.ErrorContext(ctx, "Failed to announce data export", "export_id", export.ID, "user_id", user.ID, "error", err)
+	}
+}
+
+// downloadURL signs a link to the export that stays valid until it expires.
+func (uc *DataExportUsecase) downloadURL(export *entity.DataExport) (string, error) {
+	token, err := uc.tokenservice.GenerateActionToken(services.ActionTokenClaims{
+		UserID:  export.UserID,
+		Purpose: services.PurposeDataExport,
+		Binding: export.ID.String(),
+	}, time.Until(*export.ExpiresAt))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(uc.config.BaseURL, "/") + "/api/v1/exports/download?token=" + url.QueryEscape(token), nil
+}
+
+func (uc *DataExportUsecase) archivePath(exportID uuid.UUID) string {
+	return filepath.Join(uc.config.Dir, exportID.String()+".json")
+}
+
+func (uc *DataExportUsecase) toDTO(export *entity.DataExport) (*dto.DataExportDTO, error) {
+	exportDTO := &dto.DataExportDTO{
+		ID:          export.ID,
+		Status:      export.Status,
+		Error:       export.Error,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+	if export.Status == entity.ExportStatusReady && export.ExpiresAt != nil && time.Now().Before(*export.ExpiresAt) {
+		link, err := uc.downloadURL(export)
+		if err != nil {
+			return nil, err
+		}
+		exportDTO.DownloadURL = link
+	}
+	return exportDTO, nil
+}
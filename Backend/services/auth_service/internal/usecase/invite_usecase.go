@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"time"
+
+	"auth/internal/delivery/http/dto"
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InviteConfig limits the invite codes regular users may create.
+// Administrators are not limited.
+type InviteConfig struct {
+	// MaxActive caps the codes a user may have that can still be redeemed.
+	MaxActive int
+	// MaxUses caps the registrations a single code may allow.
+	MaxUses int
+	// TTL is the default and maximum lifetime of a code.
+	TTL time.Duration
+}
+
+type InviteUsecase struct {
+	invite_repo repointerfaces.InviteRepoInterface
+	user_repo   repointerfaces.UserRepoInterface
+	audit_repo  repointerfaces.AuditRepoInterface
+	config      InviteConfig
+}
+
+func NewInviteUsecase(invite_repo repointerfaces.InviteRepoInterface, user_repo repointerfaces.UserRepoInterface, audit_repo repointerfaces.AuditRepoInterface, config InviteConfig) usecaseinterfaces.InviteUsecaseInterface {
+	return &InviteUsecase{invite_repo: invite_repo, user_repo: user_repo, audit_repo: audit_repo, config: config}
+}
+
+// CreateInvite issues a new invite code. Codes allow a single registration
+// and expire after the configured TTL unless the request says otherwise.
+func (uc *InviteUsecase) CreateInvite(ctx context.Context, userID uuid.UUID, request *dto.CreateInviteRequest) (_ *dto.InviteDTO, err error) {
+	ctx, end := startSpan(ctx, "InviteUsecase.CreateInvite")
+	defer end(&err)
+	invites := uc.invite_repo.WithContext(ctx)
+	user, err := uc.user_repo.WithContext(ctx).GetById(userID)
+	if err != nil {
+		return nil, err
+	}
+	isAdmin := user.Role == entity.RoleAdmin
+	now := time.Now().UTC()
+
+	maxUses := 1
+	if request.MaxUses != nil {
+		maxUses = *request.MaxUses
+	}
+	if !isAdmin && (maxUses < 1 || maxUses > uc.config.MaxUses) {
+		return nil, errors.New("max uses not allowed")
+	}
+
+	expiresAt := now.Add(uc.config.TTL)
+	if request.ExpiresAt != nil {
+		if !request.ExpiresAt.After(now) {
+			return nil, errors.New("expiry must be in the future")
+		}
+		if !isAdmin && request.ExpiresAt.After(expiresAt) {
+			return nil, errors.New("expiry not allowed")
+		}
+		expiresAt = request.ExpiresAt.UTC()
+	}
+
+	if !isAdmin {
+		count, err := invites.CountActive(userID, now)
+		if err != nil {
+			return nil, err
+		}
+		if count >= int64(uc.config.MaxActive) {
+			return nil, errors.New("invite limit reached")
+		}
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	invite := &entity.InviteCode{
+		ID:        uuid.New(),
+		Code:      code,
+		CreatedBy: userID,
+		Note:      request.Note,
+		MaxUses:   maxUses,
+		ExpiresAt: &expiresAt,
+		CreatedAt: now,
+	}
+	if err := invites.Create(invite); err != nil {
+		return nil, err
+	}
+	return toInviteDTO(invite, now), nil
+}
+
+func (uc *InviteUsecase) ListMyInvites(ctx context.Context, userID uuid.UUID, query *dto.InviteQuery) (_ *dto.InviteListResponse, err error) {
+	ctx, end := startSpan(ctx, "InviteUsecase.ListMyInvites")
+	defer end(&err)
+	return uc.list(ctx, &userID, query.Page, query.PageSize)
+}
+
+// RevokeInvite revokes one of the user's codes. Codes of other users and
+// already revoked ones are reported as gorm.ErrRecordNotFound.
+func (uc *InviteUsecase) RevokeInvite(ctx context.Context, userID uuid.UUID, inviteID uuid.UUID) (err error) {
+	ctx, end := startSpan(ctx, "InviteUsecase.RevokeInvite")
+	defer end(&err)
+	invite, err := uc.invite_repo.WithContext(ctx).GetById(inviteID)
+	if err != nil {
+		return err
+	}
+	if invite.CreatedBy != userID {
+		return gorm.ErrRecordNotFound
+	}
+	return uc.revoke(ctx, invite.ID)
+}
+
+func (uc *InviteUsecase) ListInvites(ctx context.Context, query *dto.InviteQuery) (_ *dto.InviteListResponse, err error) {
+	ctx, end := startSpan(ctx, "InviteUsecase.ListInvites")
+	defer end(&err)
+	var createdBy *uuid.UUID
+	if query.CreatedBy != "" {
+		parsed, err := uuid.Parse(query.CreatedBy)
+		if err != nil {
+			return nil, errors.New("invalid creator id")
+		}
+		createdBy = &parsed
+	}
+	return uc.list(ctx, createdBy, query.Page, query.PageSize)
+}
+
+func (uc *InviteUsecase) RevokeAnyInvite(ctx context.Context, actor *dto.AdminActor, inviteID uuid.UUID) (err error) {
+	ctx, end := startSpan(ctx, "InviteUsecase.RevokeAnyInvite")
+	defer end(&err)
+	invite, err := uc.invite_repo.WithContext(ctx).GetById(inviteID)
+	if err != nil {
+		return err
+	}
+	if err := uc.revoke(ctx, invite.ID); err != nil {
+		return err
+	}
+	recordAudit(ctx, uc.audit_repo, actor.UserID, actor.IP, entity.AuditActionInviteRevoke, invite.CreatedBy, map[string]string{
+		"invite_id": invite.ID.String(),
+	})
+	return nil
+}
+
+func (uc *InviteUsecase) revoke(ctx context.Context, inviteID uuid.UUID) error {
+	revoked, err := uc.invite_repo.WithContext(ctx).Revoke(inviteID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (uc *InviteUsecase) list(ctx context.Context, createdBy *uuid.UUID, page, pageSize int) (*dto.InviteListResponse, error) {
+	page, pageSize = pagination(page, pageSize)
+	invites, total, err := uc.invite_repo.WithContext(ctx).List(repointerfaces.InviteFilter{
+		CreatedBy: createdBy,
+		Offset:    (page - 1) * pageSize,
+		Limit:     pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	invitesDto := []*dto.InviteDTO{}
+	for _, invite := range invites {
+		invitesDto = append(invitesDto, toInviteDTO(invite, now))
+	}
+	return &dto.InviteListResponse{Invites: invitesDto, Page: page, PageSize: pageSize, Total: total}, nil
+}
+
+// generateInviteCode returns 16 random base32 characters, which are easy to
+// read out and type.
+func generateInviteCode() (string, error) {
+	secret := make([]byte, 10)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.EncodeToString(secret), nil
+}
+
+// inviteStatus reports why a code can no longer be redeemed, or that it can.
+func inviteStatus(invite *entity.InviteCode, now time.Time) string {
+	switch {
+	case invite.RevokedAt != nil:
+		return dto.InviteStatusRevoked
+	case invite.MaxUses > 0 && invite.Uses >= invite.MaxUses:
+		return dto.InviteStatusExhausted
+	case invite.ExpiresAt != nil && !now.Before(*invite.ExpiresAt):
+		return dto.InviteStatusExpired
+	}
+	return dto.InviteStatusActive
+}
+
+func toInviteDTO(invite *entity.InviteCode, now time.Time) *dto.InviteDTO {
+	redemptions := []*dto.InviteRedemptionDTO{}
+	for _, redemption := range invite.Redemptions {
+		redemptions = append(redemptions, &dto.InviteRedemptionDTO{
+			UserID:     redemption.UserID,
+			Username:   redemption.User.Username,
+			RedeemedAt: redemption.RedeemedAt,
+		})
+	}
+	return &dto.InviteDTO{
+		ID:          invite.ID,
+		Code:        invite.Code,
+		CreatedBy:   invite.CreatedBy,
+		Note:        invite.Note,
+		MaxUses:     invite.MaxUses,
+		Uses:        invite.Uses,
+		Status:      inviteStatus(invite, now),
+		ExpiresAt:   invite.ExpiresAt,
+		RevokedAt:   invite.RevokedAt,
+		CreatedAt:   invite.CreatedAt,
+		Redemptions: redemptions,
+	}
+}
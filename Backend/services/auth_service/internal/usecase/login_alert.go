@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"auth/helper"
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	"auth/internal/domain/entity"
+	"auth/internal/services"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// passwordResetTTL is how long a reset link emailed by DenyLogin stays valid.
+	passwordResetTTL = time.Hour
+	// alertSendTimeout bounds delivery of a single new-device alert.
+	alertSendTimeout = 30 * time.Second
+)
+
+// LoginAlerts configures the notifications sent when an account is accessed
+// from an unfamiliar device or network. A nil Notifier disables them.
+type LoginAlerts struct {
+	Notifier services.Notifier
+	// Mailer delivers password reset links. It must only reach the user's
+	// mailbox, never a push gateway, as the link lets its holder choose a
+	// new password.
+	Mailer services.Notifier
+	// BaseURL is the public URL of this service, used to build the
+	// "this wasn't me" and password reset links.
+	BaseURL string
+	// LinkTTL is how long the "this wasn't me" link stays valid.
+	LinkTTL time.Duration
+	// Tasks tracks the alerts sent in the background, so shutdown can wait
+	// for them.
+	Tasks *sync.WaitGroup
+}
+
+// isFamiliarLogin reports whether session comes from a device and network
+// range the user has signed in from before. A user's first session is
+// treated as familiar.
+func (uc *UserUsecase) isFamiliarLogin(ctx context.Context, userID uuid.UUID, session *entity.Session) (bool, error) {
+	devices, ips, err := uc.session_repo.WithContext(ctx).SummarizeHistory(userID, repointerfaces.SessionHistoryFilter{})
+	if err != nil {
+		return false, err
+	}
+	if len(devices) == 0 {
+		return true, nil
+	}
+
+	knownDevice := false
+	for _, device := range devices {
+		if device.Browser == session.Browser && device.OS == session.OS && device.DeviceType == session.DeviceType {
+			knownDevice = true
+			break
+		}
+	}
+
+	knownNetwork := false
+	network := helper.IPRange(session.IP)
+	for _, ip := range ips {
+		if network != "" && helper.IPRange(ip.IP) == network {
+			knownNetwork = true
+			break
+		}
+	}
+
+	return knownDevice && knownNetwork, nil
+}
+
+// sendNewDeviceAlert notifies the user about a login from an unfamiliar
+// device. It runs in the background, outliving the login request whose ctx it
+// is given; failures are only logged.
+func (uc *UserUsecase) sendNewDeviceAlert(ctx context.Context, user *entity.User, session *entity.Session) {
+	token, err := uc.tokenservice.GenerateActionToken(services.ActionTokenClaims{
+		UserID:    user.ID,
+		SessionID: session.ID,
+		Purpose:   services.PurposeDenyLogin,
+		Binding:   passwordBinding(user.PasswordHash),
+	}, uc.alerts.LinkTTL)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to create login alert link", "user_id", user.ID, "error", err)
+		return
+	}
+	link := uc.actionLink("/api/v1/auth/not-me", token)
+
+	device := fmt.Sprintf("%s on %s (%s)", session.Browser, session.OS, session.DeviceType)
+	if session.DeviceName != "" {
+		device = session.DeviceName + ", " + device
+	}
+	when := session.CreatedAt.UTC().Format(time.RFC1123)
+
+	notification := services.Notification{
+		Kind:    "new_device_login",
+		UserID:  user.ID,
+		Email:   user.Email,
+		Subject: "New sign-in to your account",
+		Body: fmt.Sprintf("Hi %s,\n\nYour account was just accessed from a new device or location.\n\n"+
+			"Device: %s\nIP address: %s\nTime: %s\n\n"+
+			"If this was you, you can ignore this message.\n"+
+			"If it wasn't, open the link below to sign this device out. We will then email you a link to reset your password:\n%s\n",
+			user.FullName, device, session.IP, when, link),
+		Data: map[string]string{
+			"session_id":  session.ID.String(),
+			"device":      device,
+			"ip":          session.IP,
+			"time":        when,
+			"not_me_link": link,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), alertSendTimeout)
+	defer cancel()
+	if err := uc.alerts.Notifier.Notify(ctx, notification); err != nil {
+		slog.ErrorContext(ctx, "Failed to send new device alert", "user_id", user.ID, "error", err)
+	}
+}
+
+// DenyLogin handles the "this wasn't me" link once the user has confirmed
+// it: it signs the account out everywhere, requires a password reset before
+// the next login and emails a link for performing that reset.
+//
+// The link is bound to the password hash and stops working once the reset
+// is required, so it can be used only once and never grants access itself.
+func (uc *UserUsecase) DenyLogin(ctx context.Context, token string) (err error) {
+	ctx, end := startSpan(ctx, "UserUsecase.DenyLogin")
+	defer end(&err)
+	claims, err := uc.tokenservice.ParseActionToken(token, services.PurposeDenyLogin)
+	if err != nil {
+		return errors.New("invalid or expired link")
+	}
+
+	users := uc.user_repo.WithContext(ctx)
+	user, err := users.GetById(claims.UserID)
+	if err != nil {
+		return err
+	}
+	if user.PasswordResetRequired || claims.Binding != passwordBinding(user.PasswordHash) {
+		return errors.New("invalid or expired link")
+	}
+
+	// Once started, the sign-out and reset must not be cut short by the
+	// client going away, or the account could be left half locked down
+	ctx = context.WithoutCancel(ctx)
+	users = uc.user_repo.WithContext(ctx)
+	if err := signOutEverywhere(uc.session_repo.WithContext(ctx), uc.token_repo.WithContext(ctx), user.ID, entity.RevokeReasonReportedNotMe); err != nil {
+		return err
+	}
+	// The reset flag consumes the link, so it is only set once the email is
+	// out; until then the user can open the link again
+	if err := uc.sendPasswordResetLink(ctx, user); err != nil {
+		return err
+	}
+	return users.SetPasswordResetRequired(user.ID, true)
+}
+
+// sendPasswordResetLink emails the user a link for choosing a new password.
+// The link is bound to the current password hash, so it stops working once used.
+func (uc *UserUsecase) sendPasswordResetLink(ctx context.Context, user *entity.User) error {
+	if uc.alerts.Mailer == nil {
+		return errors.New("password reset email is not configured")
+	}
+	token, err := uc.tokenservice.GenerateActionToken(services.ActionTokenClaims{
+		UserID:  user.ID,
+		Purpose: services.PurposePasswordReset,
+		Binding: passwordBinding(user.PasswordHash),
+	}, passwordResetTTL)
+	if err != nil {
+		return fmt.Errorf("failed to create password reset link: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, alertSendTimeout)
+	defer cancel()
+	err = uc.alerts.Mailer.Notify(ctx, services.Notification{
+		Kind:    "password_reset",
+		UserID:  user.ID,
+		Email:   user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nYour account has been signed out of every device. "+
+			"Open the link below within %s to choose a new password:\n%s\n",
+			user.FullName, passwordResetTTL, uc.actionLink("/api/v1/auth/password/reset", token)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+	return nil
+}
+
+// actionLink builds a public URL for path carrying an action token.
+func (uc *UserUsecase) actionLink(path string, token string) string {
+	return strings.TrimRight(uc.alerts.BaseURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+// ResetPassword sets a new password using a token emailed by DenyLogin. The
+// token is bound to the old password hash, so it stops working once used.
+func (uc *UserUsecase) ResetPassword(ctx context.Context, token string, newPassword string) (err error) {
+	ctx, end := startSpan(ctx, "UserUsecase.ResetPassword")
+	defer end(&err)
+	claims, err := uc.tokenservice.ParseActionToken(token, services.PurposePasswordReset)
+	if err != nil {
+		return errors.New("invalid or expired link")
+	}
+
+	users := uc.user_repo.WithContext(ctx)
+	user, err := users.GetById(claims.UserID)
+	if err != nil {
+		return err
+	}
+	if claims.Binding != passwordBinding(user.PasswordHash) {
+		return errors.New("invalid or expired link")
+	}
+
+	hashedPassword, err := hashPassword(ctx, newPassword)
+	if err != nil {
+		return err
+	}
+	// The new password and the sign-out go together; a cancelled request
+	// must not leave the old sessions alive after the password changed
+	ctx = context.WithoutCancel(ctx)
+	if err := uc.user_repo.WithContext(ctx).UpdatePassword(user.ID, hashedPassword); err != nil {
+		return err
+	}
+	return signOutEverywhere(uc.session_repo.WithContext(ctx), uc.token_repo.WithContext(ctx), user.ID, entity.RevokeReasonPasswordReset)
+}
+
+// passwordBinding fingerprints a password hash without exposing it.
+func passwordBinding(passwordHash string) string {
+	sum := sha256.Sum256([]byte(passwordHash))
+	return hex.EncodeToString(sum[:8])
+}
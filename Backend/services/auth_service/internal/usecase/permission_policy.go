@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"slices"
+
+	"auth/internal/domain/entity"
+)
+
+// PermissionPolicy maps roles to the permissions they grant. Global roles
+// apply everywhere; community roles only within the community the user holds
+// them in.
+type PermissionPolicy struct {
+	Global    map[string][]string
+	Community map[string][]string
+}
+
+// DefaultPermissionPolicy returns the policy the service runs with. Each
+// community role includes the permissions of the roles below it.
+func DefaultPermissionPolicy() PermissionPolicy {
+	member := []string{
+		entity.PermissionCommunityRead,
+		entity.PermissionMembersRead,
+		entity.PermissionPostsCreate,
+	}
+	moderator := append(slices.Clone(member),
+		entity.PermissionMembersInvite,
+		entity.PermissionMembersRemove,
+		entity.PermissionJoinRequestsReview,
+		entity.PermissionPostsDelete,
+	)
+	owner := append(slices.Clone(moderator),
+		entity.PermissionMembersBan,
+		entity.PermissionMembersManageRoles,
+		entity.PermissionCommunityUpdate,
+		entity.PermissionCommunityDelete,
+	)
+
+	return PermissionPolicy{
+		Global: map[string][]string{
+			entity.RoleUser:      {entity.PermissionCommunitiesCreate},
+			entity.RoleModerator: {entity.PermissionCommunitiesCreate, entity.PermissionUsersRead, entity.PermissionAuditRead},
+			entity.RoleAdmin:     {entity.PermissionAll},
+		},
+		Community: map[string][]string{
+			entity.CommunityRoleMember:    member,
+			entity.CommunityRoleModerator: moderator,
+			entity.CommunityRoleOwner:     owner,
+		},
+	}
+}
+
+// globalRoleFor returns the first of roles that grants permission everywhere.
+func (p PermissionPolicy) globalRoleFor(roles []string, permission string) (string, bool) {
+	for _, role := range roles {
+		if grants(p.Global[role], permission) {
+			return role, true
+		}
+	}
+	return "", false
+}
+
+// communityAllows reports whether the community role grants permission.
+func (p PermissionPolicy) communityAllows(role string, permission string) bool {
+	return grants(p.Community[role], permission)
+}
+
+// decide is the evaluation behind every permission check. The global roles
+// are consulted first, then communityRole, the role held in the community the
+// check is about, if any. It returns whether permission is granted and why.
+func (p PermissionPolicy) decide(roles []string, communityRole string, permission string) (bool, string) {
+	if role, ok := p.globalRoleFor(roles, permission); ok {
+		return true, "granted by role " + role
+	}
+	if communityRole != "" && p.communityAllows(communityRole, permission) {
+		return true, "granted by community role " + communityRole
+	}
+	return false, "permission not granted"
+}
+
+func grants(granted []string, permission string) bool {
+	return slices.Contains(granted, entity.PermissionAll) || slices.Contains(granted, permission)
+}
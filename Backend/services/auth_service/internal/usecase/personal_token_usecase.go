@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"auth/helper"
+	"auth/internal/delivery/http/dto"
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+	"auth/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxPersonalTokens caps the active tokens a user may hold.
+	maxPersonalTokens = 50
+	// tokenTouchInterval throttles LastUsedAt writes for personal access tokens.
+	tokenTouchInterval = time.Minute
+)
+
+type PersonalTokenUsecase struct {
+	token_repo repointerfaces.PersonalTokenRepoInterface
+	user_repo  repointerfaces.UserRepoInterface
+}
+
+func NewPersonalTokenUsecase(token_repo repointerfaces.PersonalTokenRepoInterface, user_repo repointerfaces.UserRepoInterface) usecaseinterfaces.PersonalTokenUsecaseInterface {
+	return &PersonalTokenUsecase{token_repo: token_repo, user_repo: user_repo}
+}
+
+// CreateToken issues a new token. The admin scope can only be granted by
+// administrators.
+func (uc *PersonalTokenUsecase) CreateToken(ctx context.Context, userID uuid.UUID, request *dto.CreatePersonalTokenRequest) (_ *dto.CreatedPersonalTokenResponse, err error) {
+	ctx, end := startSpan(ctx, "PersonalTokenUsecase.CreateToken")
+	defer end(&err)
+	now := time.Now().UTC()
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(now) {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	user, err := uc.user_repo.WithContext(ctx).GetById(userID)
+	if err != nil {
+		return nil, err
+	}
+	scopes := normalizeScopes(request.Scopes)
+	if slices.Contains(scopes, entity.ScopeAdmin) && user.Role != entity.RoleAdmin {
+		return nil, errors.New("scope not allowed")
+	}
+
+	tokens := uc.token_repo.WithContext(ctx)
+	count, err := tokens.CountActive(userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxPersonalTokens {
+		return nil, errors.New("token limit reached")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	raw := entity.PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	token := &entity.PersonalAccessToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      request.Name,
+		TokenHash: helper.HashTokenSHA512(raw),
+		Hint:      raw[:len(entity.PersonalAccessTokenPrefix)+4],
+		Scopes:    strings.Join(scopes, " "),
+		ExpiresAt: request.ExpiresAt,
+		CreatedAt: now,
+	}
+	if err := tokens.Create(token); err != nil {
+		return nil, err
+	}
+
+	return &dto.CreatedPersonalTokenResponse{PersonalTokenDTO: *toPersonalTokenDTO(token), Token: raw}, nil
+}
+
+func (uc *PersonalTokenUsecase) ListTokens(ctx context.Context, userID uuid.UUID) (_ []*dto.PersonalTokenDTO, err error) {
+	ctx, end := startSpan(ctx, "PersonalTokenUsecase.ListTokens")
+	defer end(&err)
+	tokens, err := uc.token_repo.WithContext(ctx).ListActive(userID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	tokensDto := []*dto.PersonalTokenDTO{}
+	for _, token := range tokens {
+		tokensDto = append(tokensDto, toPersonalTokenDTO(token))
+	}
+	return tokensDto, nil
+}
+
+// RevokeToken revokes one of the user's tokens. Tokens of other users and
+// already revoked ones are reported as gorm.ErrRecordNotFound.
+func (uc *PersonalTokenUsecase) RevokeToken(ctx context.Context, userID uuid.UUID, tokenID uuid.UUID) (err error) {
+	ctx, end := startSpan(ctx, "PersonalTokenUsecase.RevokeToken")
+	defer end(&err)
+	revoked, err := uc.token_repo.WithContext(ctx).Revoke(tokenID, userID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (uc *PersonalTokenUsecase) Authenticate(ctx context.Context, raw string) (_ *dto.TokenPrincipal, err error) {
+	ctx, end := startSpan(ctx, "PersonalTokenUsecase.Authenticate")
+	defer end(&err)
+	tokens := uc.token_repo.WithContext(ctx)
+	token, err := tokens.GetByHash(helper.HashTokenSHA512(raw))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid token")
+		}
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if token.RevokedAt != nil || (token.ExpiresAt != nil && !now.Before(*token.ExpiresAt)) {
+		return nil, errors.New("invalid token")
+	}
+
+	user, err := uc.user_repo.WithContext(ctx).GetById(token.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccountStatus(user, now); err != nil {
+		return nil, err
+	}
+	// Tokens are revoked with the sessions when a reset becomes required;
+	// this also rejects any created in between.
+	if user.PasswordResetRequired {
+		return nil, errors.New("password reset required")
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= tokenTouchInterval {
+		if err := tokens.UpdateLastUsed(token.ID, now); err != nil {
+			slog.WarnContext(ctx, "Failed to update last used time of access token", "token_id", token.ID, "error", err)
+		}
+	}
+
+	return &dto.TokenPrincipal{
+		UserID:  user.ID,
+		TokenID: token.ID,
+		Scopes:  strings.Fields(token.Scopes),
+		Roles:   []string{user.Role},
+	}, nil
+}
+
+// normalizeScopes removes duplicates and puts scopes in their documented order.
+func normalizeScopes(scopes []string) []string {
+	normalized := []string{}
+	for _, scope := range entity.AllScopes {
+		if slices.Contains(scopes, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized
+}
+
+func toPersonalTokenDTO(token *entity.PersonalAccessToken) *dto.PersonalTokenDTO {
+	return &dto.PersonalTokenDTO{
+		ID:         token.ID,
+		Name:       token.Name,
+		Hint:       token.Hint,
+		Scopes:     strings.Fields(token.Scopes),
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
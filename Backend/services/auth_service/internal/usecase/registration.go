@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"auth/internal/domain/entity"
+)
+
+// Registration modes.
+const (
+	// RegistrationModeOpen lets anyone register. Invite codes are optional but
+	// still recorded when given.
+	RegistrationModeOpen = "open"
+	// RegistrationModeInviteOnly requires a valid invite code.
+	RegistrationModeInviteOnly = "invite_only"
+	// RegistrationModeClosed rejects every registration.
+	RegistrationModeClosed = "closed"
+)
+
+// normalizeInviteCode makes codes case-insensitive and tolerant of stray
+// whitespace.
+func normalizeInviteCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// createRegisteredUser stores a new user, redeeming inviteCode when one was
+// given, after checking the registration mode.
+func (uc *UserUsecase) createRegisteredUser(ctx context.Context, user *entity.User, inviteCode string) (*entity.User, error) {
+	code := normalizeInviteCode(inviteCode)
+	switch uc.registrationMode {
+	case RegistrationModeClosed:
+		return nil, errors.New("registration is closed")
+	case RegistrationModeInviteOnly:
+		if code == "" {
+			return nil, errors.New("invite code required")
+		}
+	}
+	users := uc.user_repo.WithContext(ctx)
+	if code == "" {
+		return users.Create(user)
+	}
+	return users.CreateWithInvite(user, code, time.Now().UTC())
+}
+
+func (uc *UserUsecase) RegistrationMode() string {
+	return uc.registrationMode
+}
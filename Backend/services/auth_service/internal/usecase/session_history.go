@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"auth/internal/delivery/http/dto"
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultHistoryPageSize = 20
+	maxHistoryPageSize     = 100
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// ListSessionHistory pages through all of the user's sessions, newest first,
+// and summarises the devices and IPs seen in the requested date range.
+func (uc *SessionUsecase) ListSessionHistory(ctx context.Context, userID uuid.UUID, query *dto.SessionHistoryQuery) (_ *dto.SessionHistoryResponse, err error) {
+	ctx, end := startSpan(ctx, "SessionUsecase.ListSessionHistory")
+	defer end(&err)
+	repo := uc.repo.WithContext(ctx)
+	limit := query.Limit
+	if limit <= 0 || limit > maxHistoryPageSize {
+		limit = defaultHistoryPageSize
+	}
+	filter := repointerfaces.SessionHistoryFilter{From: query.From, To: query.To, Limit: limit + 1}
+	if query.Cursor != "" {
+		createdAt, id, err := decodeHistoryCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.AfterCreatedAt, filter.AfterID = createdAt, &id
+	}
+
+	sessions, err := repo.ListHistory(userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.SessionHistoryResponse{Sessions: []*dto.SessionHistoryItemDTO{}}
+	if len(sessions) > limit {
+		sessions = sessions[:limit]
+		last := sessions[limit-1]
+		response.NextCursor = encodeHistoryCursor(last.CreatedAt, last.ID)
+	}
+	now := time.Now().UTC()
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, &dto.SessionHistoryItemDTO{
+			SessionResponseDTO: *toSessionDTO(session),
+			Status:             uc.policy.sessionStatus(session, now),
+			CreatedAt:          session.CreatedAt,
+			ExpiresAt:          session.ExpiresAt,
+			RevokedAt:          session.RevokedAt,
+			RevokedReason:      session.RevokedReason,
+		})
+	}
+
+	devices, ips, err := repo.SummarizeHistory(userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	response.Summary = dto.SessionHistorySummaryDTO{
+		DistinctDevices: len(devices),
+		DistinctIPs:     len(ips),
+		Devices:         make([]dto.DeviceUsageDTO, 0, len(devices)),
+		IPs:             make([]dto.IPUsageDTO, 0, len(ips)),
+	}
+	for _, device := range devices {
+		response.Summary.Devices = append(response.Summary.Devices, dto.DeviceUsageDTO(device))
+	}
+	for _, ip := range ips {
+		response.Summary.IPs = append(response.Summary.IPs, dto.IPUsageDTO(ip))
+	}
+
+	return response, nil
+}
+
+// History cursors are opaque to clients: the creation time and ID of the
+// last session on the previous page.
+func encodeHistoryCursor(createdAt time.Time, id uuid.UUID) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeHistoryCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, errInvalidCursor
+	}
+	ts, rawID, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, uuid.Nil, errInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, uuid.Nil, errInvalidCursor
+	}
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return time.Time{}, uuid.Nil, errInvalidCursor
+	}
+	return createdAt, id, nil
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"time"
+
+	"auth/internal/delivery/http/dto"
+	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
+	"auth/internal/domain/entity"
+)
+
+// SessionPolicy controls how long a session stays usable and how many
+// sessions a user may hold at once.
+type SessionPolicy struct {
+	// TTL is the lifetime granted to a session by each sliding renewal.
+	TTL time.Duration
+	// IdleTimeout ends a session that has not been used for this long.
+	// Zero disables the idle check.
+	IdleTimeout time.Duration
+	// MaxLifetime is the absolute cap on a session's age. When set, every
+	// refresh slides the expiry forward by TTL (and rotates the refresh token)
+	// without ever passing CreatedAt+MaxLifetime. Zero keeps the fixed expiry
+	// assigned at login.
+	MaxLifetime time.Duration
+	// TouchInterval throttles LastUsedAt writes: activity is only persisted
+	// when the stored value is at least this old.
+	TouchInterval time.Duration
+	// MaxSessions caps the active sessions per user. Zero means unlimited.
+	MaxSessions int
+	// MaxSessionsByRole overrides MaxSessions for users with the given role.
+	MaxSessionsByRole map[string]int
+	// EvictLRU makes a login beyond the cap revoke the least recently used
+	// session instead of being rejected.
+	EvictLRU bool
+}
+
+// isActive reports whether session is neither revoked, expired nor idle at now.
+func (p SessionPolicy) isActive(session *entity.Session, now time.Time) bool {
+	if session.RevokedAt != nil || !now.Before(session.ExpiresAt) {
+		return false
+	}
+	if p.IdleTimeout > 0 && now.Sub(session.LastUsedAt) >= p.IdleTimeout {
+		return false
+	}
+	return true
+}
+
+// sessionStatus describes session at now for the login history.
+func (p SessionPolicy) sessionStatus(session *entity.Session, now time.Time) string {
+	switch {
+	case session.RevokedAt != nil:
+		return dto.SessionStatusRevoked
+	case !now.Before(session.ExpiresAt):
+		return dto.SessionStatusExpired
+	case !p.isActive(session, now):
+		return dto.SessionStatusIdle
+	}
+	return dto.SessionStatusActive
+}
+
+// needsTouch reports whether LastUsedAt is stale enough to be written again.
+func (p SessionPolicy) needsTouch(session *entity.Session, now time.Time) bool {
+	return now.Sub(session.LastUsedAt) >= p.TouchInterval
+}
+
+// slidingEnabled reports whether refreshes extend the session expiry.
+func (p SessionPolicy) slidingEnabled() bool {
+	return p.MaxLifetime > 0 && p.TTL > 0
+}
+
+// initialExpiry is the expiry of a session created at now.
+func (p SessionPolicy) initialExpiry(now time.Time) time.Time {
+	if p.MaxLifetime > 0 && p.MaxLifetime < p.TTL {
+		return now.Add(p.MaxLifetime)
+	}
+	return now.Add(p.TTL)
+}
+
+// renewedExpiry is the expiry a session gets when renewed at now.
+func (p SessionPolicy) renewedExpiry(session *entity.Session, now time.Time) time.Time {
+	expiresAt := now.Add(p.TTL)
+	if limit := session.CreatedAt.Add(p.MaxLifetime); expiresAt.After(limit) {
+		expiresAt = limit
+	}
+	return expiresAt
+}
+
+// sessionLimit returns the session cap for a user with role. A zero Max means
+// the user is not limited.
+func (p SessionPolicy) sessionLimit(role string, now time.Time) repointerfaces.SessionLimit {
+	limit := repointerfaces.SessionLimit{Max: p.MaxSessions, EvictLRU: p.EvictLRU}
+	if max, ok := p.MaxSessionsByRole[role]; ok {
+		limit.Max = max
+	}
+	if p.IdleTimeout > 0 {
+		limit.IdleSince = now.Add(-p.IdleTimeout)
+	}
+	return limit
+}
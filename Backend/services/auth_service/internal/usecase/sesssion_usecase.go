@@ -5,26 +5,36 @@ import (
 	"auth/internal/delivery/http/dto"
 	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
 	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
+	"auth/internal/domain/entity"
+	"auth/internal/infrastructure/metrics"
 	"auth/internal/services"
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	// "golang.org/x/crypto/bcrypt"
 )
 
 type SessionUsecase struct {
 	repo repointerfaces.SessionRepoInterface
+	user_repo repointerfaces.UserRepoInterface
+	community_repo repointerfaces.CommunityRepoInterface
 	tokenService services.TokenService
+	policy SessionPolicy
 }
 
-func NewSessionUsecase(repo repointerfaces.SessionRepoInterface, tokenservice services.TokenService) usecaseinterfaces.SessionUsecaseInterface{
-	return &SessionUsecase{repo:repo, tokenService: tokenservice}
+func NewSessionUsecase(repo repointerfaces.SessionRepoInterface, user_repo repointerfaces.UserRepoInterface, community_repo repointerfaces.CommunityRepoInterface, tokenservice services.TokenService, policy SessionPolicy) usecaseinterfaces.SessionUsecaseInterface{
+	return &SessionUsecase{repo:repo, user_repo: user_repo, community_repo: community_repo, tokenService: tokenservice, policy: policy}
 }
 
-func (uc *SessionUsecase) ListActiveSessions(userID uuid.UUID) ([]*dto.SessionResponseDTO, error){
-	sessions, err := uc.repo.GetAll(userID)
+func (uc *SessionUsecase) ListActiveSessions(ctx context.Context, userID uuid.UUID) (_ []*dto.SessionResponseDTO, err error){
+	ctx, end := startSpan(ctx, "SessionUsecase.ListActiveSessions")
+	defer end(&err)
+	sessions, err := uc.repo.WithContext(ctx).GetAll(userID)
 	if err != nil {
 		return nil, err
 	}
@@ -32,81 +42,259 @@ func (uc *SessionUsecase) ListActiveSessions(userID uuid.UUID) ([]*dto.SessionRe
 		return []*dto.SessionResponseDTO{}, nil
 	}
 
-	var sessionsDto []*dto.SessionResponseDTO
+	sessionsDto := []*dto.SessionResponseDTO{}
+	now := time.Now().UTC()
 	for _, session := range sessions {
-		sessionDto := &dto.SessionResponseDTO{
-			ID: session.ID,
-			UserID: session.UserID,
-			UserAgent: session.UserAgent,
-			IP: session.IP,
-			LastUsedAt: session.LastUsedAt,
+		if !uc.policy.isActive(session, now) {
+			continue
 		}
-
-		sessionsDto = append(sessionsDto, sessionDto)
+		sessionsDto = append(sessionsDto, toSessionDTO(session))
 	}
 
 	return sessionsDto, nil
 }
-func (uc *SessionUsecase) GetSession(sessionID uuid.UUID) (*dto.SessionResponseDTO, error){
-	session, err := uc.repo.GetById(sessionID)
+func (uc *SessionUsecase) GetSession(ctx context.Context, sessionID uuid.UUID) (_ *dto.SessionResponseDTO, err error){
+	ctx, end := startSpan(ctx, "SessionUsecase.GetSession")
+	defer end(&err)
+	session, err := uc.repo.WithContext(ctx).GetById(sessionID)
 	if err != nil {
 		return nil, err
 	}
-	sessionDto := &dto.SessionResponseDTO{
-			ID: session.ID,
-			UserID: session.UserID,
-			UserAgent: session.UserAgent,
-			IP: session.IP,
-			LastUsedAt: session.LastUsedAt,
-		}
-	return sessionDto, nil
+	return toSessionDTO(session), nil
+}
+func (uc *SessionUsecase) Logout(ctx context.Context, sessionID uuid.UUID) (err error){
+	ctx, end := startSpan(ctx, "SessionUsecase.Logout")
+	defer end(&err)
+	return uc.repo.WithContext(ctx).RevokeSession(sessionID, entity.RevokeReasonLogout)
+}
+func (uc *SessionUsecase) LogoutAllExcept(ctx context.Context, userID uuid.UUID, keepSessionID uuid.UUID) (err error){
+	ctx, end := startSpan(ctx, "SessionUsecase.LogoutAllExcept")
+	defer end(&err)
+	return uc.repo.WithContext(ctx).RevokeAllExceptCurrent(userID,keepSessionID, entity.RevokeReasonLogoutOthers)
 }
-func (uc *SessionUsecase) Logout(sessionID uuid.UUID) error{
-	return uc.repo.RevokeSession(sessionID)
+// RevokeUserSession revokes one of the user's active sessions. Sessions that
+// belong to someone else, or are already revoked, expired or idle, are reported as
+// gorm.ErrRecordNotFound so callers cannot probe other users' session IDs.
+func (uc *SessionUsecase) RevokeUserSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) (err error){
+	ctx, end := startSpan(ctx, "SessionUsecase.RevokeUserSession")
+	defer end(&err)
+	repo := uc.repo.WithContext(ctx)
+	session, err := repo.GetById(sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID || !uc.policy.isActive(session, time.Now().UTC()) {
+		return gorm.ErrRecordNotFound
+	}
+	return repo.RevokeSession(sessionID, entity.RevokeReasonUserRevoked)
 }
-func (uc *SessionUsecase) LogoutAllExcept(userID uuid.UUID, keepSessionID uuid.UUID) error{
-	return uc.repo.RevokeAllExceptCurrent(userID,keepSessionID)
+// Refresh issues a new access token for the session behind refreshToken. When
+// sliding expiry is enabled the session is renewed and a rotated refresh token
+// is returned as well; otherwise the returned refresh token is empty.
+func (uc *SessionUsecase) Refresh(ctx context.Context, refreshToken string, client *dto.ClientInfo) (_ string, _ string, err error){
+	ctx, end := startSpan(ctx, "SessionUsecase.Refresh")
+	defer end(&err)
+	accessToken, newRefreshToken, err := uc.refresh(ctx, refreshToken, client)
+	metrics.Refreshes.WithLabelValues(refreshOutcome(err)).Inc()
+	return accessToken, newRefreshToken, err
 }
-func (uc *SessionUsecase) Refresh(refreshToken string) (string, error){
+
+func (uc *SessionUsecase) refresh(ctx context.Context, refreshToken string, client *dto.ClientInfo) (string, string, error){
+	repo := uc.repo.WithContext(ctx)
 	// 1. Parse refresh token using the injected service
     claims, err := uc.tokenService.ParseRefreshToken(refreshToken)
     if err != nil {
-        return "",  err
+        return "", "", err
     }
 
     // 2. Load session from DB
-    session, err := uc.repo.GetById(claims.SessionID)
+    session, err := repo.GetWithTokenHash(claims.SessionID)
+    if err != nil {
+        return "", "", err
+    }
+    // The account is checked first: leaving the active state revokes every
+    // session, and the client should learn why rather than only that it was revoked.
+    // The roles in the new access token also come from the account as it is now.
+    user, err := uc.user_repo.WithContext(ctx).GetById(session.UserID)
     if err != nil {
-        return "",err
+        return "", "", err
+    }
+    now := time.Now().UTC()
+    if err := checkAccountStatus(user, now); err != nil {
+        return "", "", err
     }
-    if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
-        return "",  errors.New("session expired or revoked")
+    if !uc.policy.isActive(session, now) {
+        return "", "", errors.New("session expired or revoked")
     }
 
     // 3. Verify refresh token hash (if stored)
 	
 	if matched := helper.CompareTokenSHA512(refreshToken,session.TokenHash); !matched{
-		return "",  errors.New("refresh token mismatch")
+		return "", "", errors.New("refresh token mismatch")
+	}
+
+	// 4. Record the device the refresh came from
+	if client != nil {
+		applyClientInfo(session, client)
+		if err := repo.UpdateDevice(session); err != nil {
+			return "", "", fmt.Errorf("failed to update session device: %w", err)
+		}
+	}
+
+	// 5. Renew the session, rotating the refresh token when expiry slides
+	var newRefreshToken string
+	if uc.policy.slidingEnabled() {
+		newRefreshToken, err = uc.tokenService.GenerateRefreshToken(session.UserID, session.ID)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to create refresh token: %w", err)
+		}
+		err = repo.Renew(session.ID, session.TokenHash, helper.HashTokenSHA512(newRefreshToken), uc.policy.renewedExpiry(session, now))
+	} else {
+		err = repo.UpdateLastUsed(session.ID)
+	}
+	if errors.Is(err, repointerfaces.ErrRefreshTokenMismatch) {
+		// Another refresh rotated the token first: the same refresh token was
+		// presented twice, which is treated as a sign it was stolen
+		if revokeErr := repo.RevokeSession(session.ID, entity.RevokeReasonTokenReuse); revokeErr != nil {
+			slog.ErrorContext(ctx, "Failed to revoke session after refresh token reuse", "session_id", session.ID, "error", revokeErr)
+		}
+		return "", "", errors.New("refresh token mismatch")
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Revoked between the check above and the renewal
+		return "", "", errors.New("session expired or revoked")
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to renew session: %w", err)
 	}
     
-	// 4. Generate new tokens
-    accessToken, err := uc.tokenService.GenerateAccessToken(session.UserID, session.ID, []string{})
+	// 6. Generate new tokens
+    community, err := uc.communityClaim(ctx, session)
+    if err != nil {
+        return "", "", err
+    }
+    accessToken, err := uc.tokenService.GenerateAccessToken(session.UserID, session.ID, []string{user.Role}, community)
     if err != nil {
-        return "", fmt.Errorf("failed to create access token: %w", err)
+        return "", "", fmt.Errorf("failed to create access token: %w", err)
     }
 
-    return accessToken, nil
+    return accessToken, newRefreshToken, nil
+}
+
+// SwitchCommunity makes communityID the session's active community, or clears
+// it when nil, and returns an access token that carries the change.
+func (uc *SessionUsecase) SwitchCommunity(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, communityID *uuid.UUID) (_ string, err error){
+	ctx, end := startSpan(ctx, "SessionUsecase.SwitchCommunity")
+	defer end(&err)
+	var community *services.CommunityClaim
+	if communityID != nil {
+		membership, err := uc.community_repo.WithContext(ctx).GetMembership(*communityID, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", errors.New("not a community member")
+			}
+			return "", err
+		}
+		community = &services.CommunityClaim{ID: membership.CommunityID, Role: membership.Role}
+	}
+
+	user, err := uc.user_repo.WithContext(ctx).GetById(userID)
+	if err != nil {
+		return "", err
+	}
+	if err := uc.repo.WithContext(ctx).SetActiveCommunity(sessionID, communityID); err != nil {
+		return "", fmt.Errorf("failed to switch community: %w", err)
+	}
+
+	accessToken, err := uc.tokenService.GenerateAccessToken(userID, sessionID, []string{user.Role}, community)
+	if err != nil {
+		return "", fmt.Errorf("failed to create access token: %w", err)
+	}
+	return accessToken, nil
+}
+
+// communityClaim describes the session's active community with the role the
+// user holds there now. Sessions whose user has since left the community act
+// in none.
+func (uc *SessionUsecase) communityClaim(ctx context.Context, session *entity.Session) (*services.CommunityClaim, error) {
+	if session.ActiveCommunityID == nil {
+		return nil, nil
+	}
+	membership, err := uc.community_repo.WithContext(ctx).GetMembership(*session.ActiveCommunityID, session.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load community membership: %w", err)
+	}
+	return &services.CommunityClaim{ID: membership.CommunityID, Role: membership.Role}, nil
+}
+
+// CheckAccount returns an *AccountStatusError when the user's account is not active.
+func (uc *SessionUsecase) CheckAccount(ctx context.Context, userID uuid.UUID) (err error){
+	ctx, end := startSpan(ctx, "SessionUsecase.CheckAccount")
+	defer end(&err)
+	state, err := uc.user_repo.WithContext(ctx).GetAccountState(userID)
+	if err != nil {
+		return err
+	}
+	return checkAccountState(state, time.Now().UTC())
 }
 
-func (uc *SessionUsecase) IsSessionActive(sessionID uuid.UUID) (bool, error){
-	session, err := uc.repo.GetById(sessionID)
+// IsSessionActive reports whether the session can still be used. Active
+// sessions also get their LastUsedAt refreshed, at most once per
+// TouchInterval, so the idle timeout follows real activity.
+func (uc *SessionUsecase) IsSessionActive(ctx context.Context, sessionID uuid.UUID) (_ bool, err error){
+	ctx, end := startSpan(ctx, "SessionUsecase.IsSessionActive")
+	defer end(&err)
+	repo := uc.repo.WithContext(ctx)
+	session, err := repo.GetById(sessionID)
 	if err != nil {
 		return false, fmt.Errorf("something went wrong %w", err)
 	}
 
-	if session.RevokedAt != nil || session.ExpiresAt.Before(time.Now().UTC()){
+	now := time.Now().UTC()
+	if !uc.policy.isActive(session, now){
 		return false, nil
 	}
 
+	if uc.policy.needsTouch(session, now) {
+		if err := repo.UpdateLastUsed(sessionID); err != nil {
+			slog.WarnContext(ctx, "Failed to update last used time of session", "session_id", sessionID, "error", err)
+		}
+	}
+
 	return true, nil
-}
\ No newline at end of file
+}
+
+// toSessionDTO maps a stored session to the shape returned by the API.
+func toSessionDTO(session *entity.Session) *dto.SessionResponseDTO {
+	return &dto.SessionResponseDTO{
+		ID:         session.ID,
+		UserID:     session.UserID,
+		UserAgent:  session.UserAgent,
+		IP:         session.IP,
+		Browser:    session.Browser,
+		OS:         session.OS,
+		DeviceType: session.DeviceType,
+		DeviceName: session.DeviceName,
+		LastUsedAt: session.LastUsedAt,
+	}
+}
+
+// applyClientInfo copies the request's device metadata onto session, parsing
+// the user agent into browser, OS and device type.
+func applyClientInfo(session *entity.Session, client *dto.ClientInfo) {
+	if client == nil {
+		return
+	}
+	agent := helper.ParseUserAgent(client.UserAgent)
+	session.IP = client.IP
+	session.UserAgent = client.UserAgent
+	session.Browser = agent.Browser
+	session.OS = agent.OS
+	session.DeviceType = agent.DeviceType
+	if client.DeviceName != "" {
+		session.DeviceName = client.DeviceName
+	}
+}
@@ -5,27 +5,45 @@ import (
 	repointerfaces "auth/internal/domain/contracts/repo_interfaces"
 	usecaseinterfaces "auth/internal/domain/contracts/usecase_interfaces"
 	"auth/internal/domain/entity"
+	"auth/internal/infrastructure/metrics"
 	"auth/internal/services"
+	"context"
 	"errors"
+	"log/slog"
 	// "fmt"
 	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 	"auth/helper"
 )
 
 type UserUsecase struct {
 	user_repo repointerfaces.UserRepoInterface
 	session_repo repointerfaces.SessionRepoInterface
+	token_repo repointerfaces.PersonalTokenRepoInterface
+	audit_repo repointerfaces.AuditRepoInterface
 	tokenservice services.TokenService
+	sessionPolicy SessionPolicy
+	alerts LoginAlerts
+	// deletionGracePeriod is how long a deletion request can be cancelled.
+	deletionGracePeriod time.Duration
+	// registrationMode is one of the RegistrationMode constants.
+	registrationMode string
 }
 
-func NewUserUsecase(user_repo repointerfaces.UserRepoInterface, session_repo repointerfaces.SessionRepoInterface, tokenservice services.TokenService) usecaseinterfaces.UserUsecaseInterface{
-	return &UserUsecase{user_repo:user_repo, session_repo: session_repo, tokenservice: tokenservice}
+func NewUserUsecase(user_repo repointerfaces.UserRepoInterface, session_repo repointerfaces.SessionRepoInterface, token_repo repointerfaces.PersonalTokenRepoInterface, audit_repo repointerfaces.AuditRepoInterface, tokenservice services.TokenService, sessionPolicy SessionPolicy, alerts LoginAlerts, deletionGracePeriod time.Duration, registrationMode string) usecaseinterfaces.UserUsecaseInterface{
+	return &UserUsecase{user_repo:user_repo, session_repo: session_repo, token_repo: token_repo, audit_repo: audit_repo, tokenservice: tokenservice, sessionPolicy: sessionPolicy, alerts: alerts, deletionGracePeriod: deletionGracePeriod, registrationMode: registrationMode}
 }
 
-func (uc *UserUsecase) Register(userdto *dto.RegisterUser) (*dto.UserDto, error){
+func (uc *UserUsecase) Register(ctx context.Context, userdto *dto.RegisterUser) (_ *dto.UserDto, err error){
+	ctx, end := startSpan(ctx, "UserUsecase.Register")
+	defer end(&err)
+	user, err := uc.register(ctx, userdto)
+	metrics.Registrations.WithLabelValues(registrationOutcome(err)).Inc()
+	return user, err
+}
+
+func (uc *UserUsecase) register(ctx context.Context, userdto *dto.RegisterUser) (*dto.UserDto, error){
 	user := &entity.User{
 		FullName: userdto.FullName,
 		ID: uuid.New(),
@@ -34,13 +52,13 @@ func (uc *UserUsecase) Register(userdto *dto.RegisterUser) (*dto.UserDto, error)
 		Country: userdto.Country,
 		PhoneNumber: userdto.PhoneNumber,
 	}
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(userdto.Password), bcrypt.DefaultCost)
+	hashedPassword, err := hashPassword(ctx, userdto.Password)
     if err != nil {
         return nil, err
     }
 
-    user.PasswordHash = string(hashedPassword)
-	created_user, err := uc.user_repo.Create(user)
+    user.PasswordHash = hashedPassword
+	created_user, err := uc.createRegisteredUser(ctx, user, userdto.InviteCode)
 
 	if err != nil {
 		return nil, err
@@ -58,18 +76,24 @@ func (uc *UserUsecase) Register(userdto *dto.RegisterUser) (*dto.UserDto, error)
     return created_user_dto, nil
 }
 
-func (uc *UserUsecase)	Login(identification string, password string) (*dto.UserDto,string, string, error){
-	user, err := uc.user_repo.GetByEmail(identification)
+func (uc *UserUsecase)	Login(ctx context.Context, identification string, password string, client *dto.ClientInfo) (_ *dto.UserDto, _ string, _ string, err error){
+	ctx, end := startSpan(ctx, "UserUsecase.Login")
+	defer end(&err)
+	user, refreshToken, accessToken, err := uc.login(ctx, identification, password, client)
+	metrics.Logins.WithLabelValues(loginOutcome(err)).Inc()
+	return user, refreshToken, accessToken, err
+}
+
+func (uc *UserUsecase) login(ctx context.Context, identification string, password string, client *dto.ClientInfo) (*dto.UserDto,string, string, error){
+	user, err := uc.authenticate(ctx, identification, password)
 	if err != nil {
-		user, err = uc.user_repo.GetByUsername(identification)
-		if err != nil{
-			return nil,"", "",errors.New("user not found")
-		}
+		return nil, "", "", err
 	}
-
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
-	if err != nil{
-		return nil, "", "",errors.New("invalid credentials")
+	if user.PasswordResetRequired {
+		return nil, "", "", errors.New("password reset required")
+	}
+	if err := checkAccountStatus(user, time.Now().UTC()); err != nil {
+		return nil, "", "", err
 	}
 
 	sessionId := uuid.New()
@@ -87,18 +111,33 @@ func (uc *UserUsecase)	Login(identification string, password string) (*dto.UserD
         ID:        sessionId,
         UserID:    user.ID,
         TokenHash: string(HashedToken), // store hash
-        ExpiresAt: time.Now().UTC().Add(30 * 24 * time.Hour), // 30 days
+        ExpiresAt: uc.sessionPolicy.initialExpiry(time.Now().UTC()),
         LastUsedAt: time.Now().UTC(),
         CreatedAt: time.Now().UTC(),
     }
+	applyClientInfo(session, client)
 
-	_, err = uc.session_repo.AddSession(session)
+	familiar := true
+	if uc.alerts.Notifier != nil {
+		if familiar, err = uc.isFamiliarLogin(ctx, user.ID, session); err != nil {
+			slog.WarnContext(ctx, "Could not compare login with previous sessions", "user_id", user.ID, "error", err)
+			familiar = true
+		}
+	}
+
+	if limit := uc.sessionPolicy.sessionLimit(user.Role, time.Now().UTC()); limit.Max > 0 {
+		_, err = uc.session_repo.WithContext(ctx).AddSessionWithLimit(session, limit)
+	} else {
+		_, err = uc.session_repo.WithContext(ctx).AddSession(session)
+	}
 	if err != nil {
 		return nil, "", "",err
 	}
+	if !familiar {
+		goTracked(uc.alerts.Tasks, func() { uc.sendNewDeviceAlert(ctx, user, session) })
+	}
 
-	var roles []string
-	access_token, err := uc.tokenservice.GenerateAccessToken(user.ID,session.ID,roles)
+	access_token, err := uc.tokenservice.GenerateAccessToken(user.ID,session.ID,[]string{user.Role}, nil)
 
 	if err != nil {
 		return nil, "", "", err
@@ -118,8 +157,10 @@ func (uc *UserUsecase)	Login(identification string, password string) (*dto.UserD
 	
 	
 }
-func (uc *UserUsecase)	GetUserProfile(Id uuid.UUID) (*dto.UserDto, error){
-	user, err := uc.user_repo.GetById(Id)
+func (uc *UserUsecase)	GetUserProfile(ctx context.Context, Id uuid.UUID) (_ *dto.UserDto, err error){
+	ctx, end := startSpan(ctx, "UserUsecase.GetUserProfile")
+	defer end(&err)
+	user, err := uc.user_repo.WithContext(ctx).GetById(Id)
 	if err != nil {
 		return nil, err
 	}
@@ -136,8 +177,10 @@ func (uc *UserUsecase)	GetUserProfile(Id uuid.UUID) (*dto.UserDto, error){
 	return user_dto, nil
 	
 }
-func (uc *UserUsecase)	IsVerifiedUser(Id uuid.UUID) (bool, error){
-	user, err := uc.user_repo.GetById(Id)
+func (uc *UserUsecase)	IsVerifiedUser(ctx context.Context, Id uuid.UUID) (_ bool, err error){
+	ctx, end := startSpan(ctx, "UserUsecase.IsVerifiedUser")
+	defer end(&err)
+	user, err := uc.user_repo.WithContext(ctx).GetById(Id)
 	if err != nil {
 		return false, err
 	}
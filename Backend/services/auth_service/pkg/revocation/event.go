@@ -0,0 +1,43 @@
+// Package revocation broadcasts session revocations from the auth service to
+// other community services over Redis pub/sub.
+//
+// The package belongs to the auth module, whose path ("auth") is not
+// importable from other modules, so it is internal to this service. Other
+// services consume the events directly: they subscribe to Channel, where each
+// message is one Event encoded as JSON, e.g.
+//
+//	{"session_id":"…","user_id":"…","revoked_at":"2024-01-02T15:04:05Z"}
+//
+// with UUIDs in their canonical form and revoked_at in RFC 3339. Channel and
+// the JSON field names are the contract; changing them breaks subscribers.
+// Subscriber and Set show how a Go service can keep an in-memory set of
+// revoked sessions and reject tokens carrying one of them:
+//
+//	revoked := revocation.NewSet(15 * time.Minute) // at least the access token TTL
+//	go revocation.NewSubscriber(rdb, revoked).Run(ctx)
+//	...
+//	if revoked.IsRevoked(claims.SessionID) {
+//		// reject the request
+//	}
+//
+// Pub/sub is fire-and-forget: events published while a subscriber is
+// disconnected are not replayed, so the set is a best-effort supplement to the
+// short access token lifetime rather than a replacement for it.
+package revocation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Channel is the Redis pub/sub channel revocation events are published on.
+// Other services subscribe to it by name, so it must not change.
+const Channel = "auth:sessions:revoked"
+
+// Event describes a revoked session.
+type Event struct {
+	SessionID uuid.UUID `json:"session_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
@@ -0,0 +1,32 @@
+package revocation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Publisher sends revocation events to Channel.
+type Publisher struct {
+	rdb *redis.Client
+}
+
+func NewPublisher(rdb *redis.Client) *Publisher {
+	return &Publisher{rdb: rdb}
+}
+
+// Publish sends one message per event, stopping at the first failure.
+func (p *Publisher) Publish(ctx context.Context, events ...Event) error {
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode revocation event: %w", err)
+		}
+		if err := p.rdb.Publish(ctx, Channel, payload).Err(); err != nil {
+			return fmt.Errorf("failed to publish revocation of session %s: %w", event.SessionID, err)
+		}
+	}
+	return nil
+}
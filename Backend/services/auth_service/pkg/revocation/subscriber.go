@@ -0,0 +1,108 @@
+package revocation
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Set is an in-memory, concurrency-safe set of revoked session IDs. Entries
+// are forgotten after ttl, by which time every access token issued for the
+// session has expired on its own.
+type Set struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[uuid.UUID]time.Time
+}
+
+func NewSet(ttl time.Duration) *Set {
+	return &Set{ttl: ttl, entries: make(map[uuid.UUID]time.Time)}
+}
+
+// Add records the session in event as revoked.
+func (s *Set) Add(event Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[event.SessionID] = time.Now().Add(s.ttl)
+}
+
+// IsRevoked reports whether sessionID was revoked within the last ttl.
+func (s *Set) IsRevoked(sessionID uuid.UUID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	until, ok := s.entries[sessionID]
+	return ok && time.Now().Before(until)
+}
+
+// Len returns the number of sessions currently tracked, including ones that
+// have expired but not yet been pruned.
+func (s *Set) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entries)
+}
+
+// Prune drops entries older than ttl.
+func (s *Set) Prune() {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, until := range s.entries {
+		if !now.Before(until) {
+			delete(s.entries, id)
+		}
+	}
+}
+
+// Subscriber feeds revocation events from Redis into a Set.
+type Subscriber struct {
+	rdb *redis.Client
+	set *Set
+}
+
+func NewSubscriber(rdb *redis.Client, set *Set) *Subscriber {
+	return &Subscriber{rdb: rdb, set: set}
+}
+
+// Run listens on Channel until ctx is cancelled. The Redis client reconnects
+// on its own after connection failures; malformed messages are logged and skipped.
+func (s *Subscriber) Run(ctx context.Context) error {
+	pubsub := s.rdb.Subscribe(ctx, Channel)
+	defer pubsub.Close()
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+	messages := pubsub.Channel()
+
+	pruneEvery := s.set.ttl
+	if pruneEvery <= 0 || pruneEvery > time.Minute {
+		pruneEvery = time.Minute
+	}
+	ticker := time.NewTicker(pruneEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			s.set.Prune()
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			var event Event
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				slog.Warn("Ignoring malformed revocation event", "error", err)
+				continue
+			}
+			s.set.Add(event)
+		}
+	}
+}